@@ -6,12 +6,22 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/Bryan-an/tasker-backend/pkg/admin"
 	"github.com/Bryan-an/tasker-backend/pkg/auth"
 	"github.com/Bryan-an/tasker-backend/pkg/common/db"
+	eventbus "github.com/Bryan-an/tasker-backend/pkg/common/events"
 	"github.com/Bryan-an/tasker-backend/pkg/common/middlewares"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/Bryan-an/tasker-backend/pkg/events"
+	"github.com/Bryan-an/tasker-backend/pkg/jobs"
+	"github.com/Bryan-an/tasker-backend/pkg/labels"
 	"github.com/Bryan-an/tasker-backend/pkg/settings"
+	"github.com/Bryan-an/tasker-backend/pkg/stats"
 	"github.com/Bryan-an/tasker-backend/pkg/tasks"
+	"github.com/Bryan-an/tasker-backend/pkg/timetracking"
+	"github.com/Bryan-an/tasker-backend/pkg/tokens"
 	"github.com/Bryan-an/tasker-backend/pkg/users"
+	"github.com/Bryan-an/tasker-backend/pkg/webhooks"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -26,6 +36,9 @@ func main() {
 		log.Fatal("Error loading .env file", err)
 	}
 
+	utils.LoadSigningKeys()
+	eventbus.Configure()
+
 	client = db.Connect()
 	DbName := os.Getenv("DB_NAME")
 	database = client.Database(DbName)
@@ -36,6 +49,8 @@ func main() {
 		}
 	}()
 
+	jobs.Start(database)
+
 	router := setupRouter()
 	var port string
 
@@ -57,10 +72,17 @@ func setupRouter() *gin.Engine {
 		c.String(http.StatusOK, "pong")
 	})
 
+	admin.RegisterRoutes(router, database, client)
 	auth.RegisterRoutes(router, database, client)
+	events.RegisterRoutes(router, database, client)
+	labels.RegisterRoutes(router, database, client)
 	settings.RegisterRoutes(router, database, client)
+	stats.RegisterRoutes(router, database, client)
 	tasks.RegisterRoutes(router, database, client)
+	timetracking.RegisterRoutes(router, database, client)
+	tokens.RegisterRoutes(router, database, client)
 	users.RegisterRoutes(router, database, client)
+	webhooks.RegisterRoutes(router, database, client)
 
 	return router
 }
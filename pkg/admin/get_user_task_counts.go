@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type taskCount struct {
+	Status string `bson:"_id"`
+	Total  int64  `bson:"total"`
+	Done   int64  `bson:"done"`
+}
+
+func (h handler) GetUserTaskCounts(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	tasksCollection := h.DB.Collection("tasks")
+
+	pipeline := bson.A{
+		bson.D{{Key: "$match", Value: bson.D{{Key: "user_id", Value: id}}}},
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$status"},
+			{Key: "total", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "done", Value: bson.D{{Key: "$sum", Value: bson.D{
+				{Key: "$cond", Value: bson.A{"$done", 1, 0}},
+			}}}},
+		}}},
+	}
+
+	cursor, err := tasksCollection.Aggregate(context.TODO(), pipeline)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var counts []taskCount
+
+	if err = cursor.All(context.TODO(), &counts); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	byStatus := gin.H{}
+	var total, done int64
+
+	for _, count := range counts {
+		byStatus[count.Status] = count.Total
+		total += count.Total
+		done += count.Done
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"total":     total,
+			"done":      done,
+			"by_status": byStatus,
+		},
+	})
+}
@@ -0,0 +1,82 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (h handler) GetUsers(c *gin.Context) {
+	search := c.Query("q")
+	status := c.Query("status")
+	role := c.Query("role")
+
+	page, pageSize, queryParamsErrors := utils.GetPagination(c)
+
+	if len(queryParamsErrors) > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": queryParamsErrors})
+		return
+	}
+
+	usersCollection := h.DB.Collection("users")
+	var users []models.User
+	filter := bson.M{}
+
+	if search != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(search), Options: "i"}
+
+		filter["$or"] = bson.A{
+			bson.M{"name": pattern},
+			bson.M{"email": pattern},
+		}
+	}
+
+	if status != "" {
+		filter["status"] = status
+	}
+
+	if role != "" {
+		filter["role"] = role
+	}
+
+	opts := options.Find().
+		SetProjection(bson.D{{Key: "password", Value: 0}}).
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(pageSize)).
+		SetSkip(int64((page - 1) * pageSize))
+
+	cursor, err := usersCollection.Find(context.TODO(), filter, opts)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if err = cursor.All(context.TODO(), &users); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if users == nil {
+		users = []models.User{}
+	}
+
+	totalRecords, err := usersCollection.CountDocuments(context.TODO(), filter)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       users,
+		"pagination": utils.GetPaginationInfo(page, pageSize, len(users), totalRecords),
+	})
+}
@@ -0,0 +1,33 @@
+package admin
+
+import (
+	"github.com/Bryan-an/tasker-backend/pkg/common/middlewares"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type handler struct {
+	DB     *mongo.Database
+	Client *mongo.Client
+}
+
+func RegisterRoutes(r *gin.Engine, db *mongo.Database, client *mongo.Client) {
+	h := &handler{
+		DB:     db,
+		Client: client,
+	}
+
+	routes := r.Group("/api/v1/admin")
+
+	routes.Use(middlewares.JwtAuthMiddleware(db))
+	routes.Use(middlewares.RequireScopes("admin:read", "admin:write"))
+	routes.Use(middlewares.LoadUser(db))
+	routes.GET("/users", middlewares.RequirePermission(models.PermissionReadUsers), h.GetUsers)
+	routes.GET("/users/:id", middlewares.RequirePermission(models.PermissionReadUsers), h.GetUser)
+	routes.GET("/users/:id/tasks/count", middlewares.RequirePermission(models.PermissionReadUserTasks), h.GetUserTaskCounts)
+	routes.PATCH("/users/:id/status", middlewares.RequirePermission(models.PermissionChangeStatus), h.UpdateUserStatus)
+	routes.PATCH("/users/:id/role", middlewares.RequirePermission(models.PermissionChangeRole), h.UpdateUserRole)
+	routes.GET("/retention/report", middlewares.RequirePermission(models.PermissionManageRetention), h.GetRetentionReport)
+	routes.GET("/retention/audits", middlewares.RequirePermission(models.PermissionManageRetention), h.GetPurgeAudits)
+}
@@ -0,0 +1,70 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/Bryan-an/tasker-backend/pkg/jobs"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (h handler) GetRetentionReport(c *gin.Context) {
+	audits, err := jobs.PurgeData(context.TODO(), h.DB, true)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": audits})
+}
+
+func (h handler) GetPurgeAudits(c *gin.Context) {
+	page, pageSize, queryParamsErrors := utils.GetPagination(c)
+
+	if len(queryParamsErrors) > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": queryParamsErrors})
+		return
+	}
+
+	auditsCollection := h.DB.Collection("purge_audits")
+	var audits []models.PurgeAudit
+	filter := bson.D{}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(pageSize)).
+		SetSkip(int64((page - 1) * pageSize))
+
+	cursor, err := auditsCollection.Find(context.TODO(), filter, opts)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if err = cursor.All(context.TODO(), &audits); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if audits == nil {
+		audits = []models.PurgeAudit{}
+	}
+
+	totalRecords, err := auditsCollection.CountDocuments(context.TODO(), filter)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       audits,
+		"pagination": utils.GetPaginationInfo(page, pageSize, len(audits), totalRecords),
+	})
+}
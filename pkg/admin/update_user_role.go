@@ -0,0 +1,118 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type roleInput struct {
+	Role *string `json:"role" binding:"required,oneof=user support admin"`
+}
+
+func (h handler) UpdateUserRole(c *gin.Context) {
+	userId := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(userId)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var input roleInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	if isCurrentUser(c, id) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "you can't change your own role",
+		})
+
+		return
+	}
+
+	if !outranksRole(c, *input.Role) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error": "you can only grant roles lower than your own",
+		})
+
+		return
+	}
+
+	coll := h.DB.Collection("users")
+	filter := bson.D{{Key: "_id", Value: id}}
+	var user models.User
+
+	if err := coll.FindOne(context.TODO(), filter).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("user not found with id '%s'", userId),
+			})
+
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if !outranksUser(c, user) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error": "you can't change the role of a user with the same or a higher role",
+		})
+
+		return
+	}
+
+	filter = append(filter, bson.E{Key: "role", Value: user.Role})
+
+	update := bson.D{
+		{
+			Key: "$set",
+			Value: bson.D{
+				{Key: "role", Value: input.Role},
+				{Key: "updated_at", Value: time.Now()},
+			},
+		},
+	}
+
+	result, err := coll.UpdateOne(context.TODO(), filter, update)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"error": "user role changed while processing the request, please try again",
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "user role updated successfully",
+	})
+}
@@ -0,0 +1,146 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/lifecycle"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type statusInput struct {
+	Status *string `json:"status" binding:"required,oneof=active created unsubscribed suspended pending_deletion deleted"`
+}
+
+func (h handler) UpdateUserStatus(c *gin.Context) {
+	userId := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(userId)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var input statusInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	if isCurrentUser(c, id) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "you can't change your own status",
+		})
+
+		return
+	}
+
+	coll := h.DB.Collection("users")
+	filter := bson.D{{Key: "_id", Value: id}}
+	var user models.User
+
+	if err := coll.FindOne(context.TODO(), filter).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("user not found with id '%s'", userId),
+			})
+
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if !outranksUser(c, user) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error": "you can't change the status of a user with the same or a higher role",
+		})
+
+		return
+	}
+
+	if !lifecycle.CanTransition(*user.Status, *input.Status) {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"error": fmt.Sprintf("user status can't change from '%s' to '%s'", *user.Status, *input.Status),
+		})
+
+		return
+	}
+
+	result, err := lifecycle.Transition(context.TODO(), coll, filter, *input.Status)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"error": "user status changed while processing the request, please try again",
+		})
+
+		return
+	}
+
+	if *input.Status == lifecycle.StatusDeleted {
+		if err := lifecycle.Anonymize(context.TODO(), coll, id); err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "user status updated successfully",
+	})
+}
+
+func outranksUser(c *gin.Context, target models.User) bool {
+	targetRole := models.RoleUser
+
+	if target.Role != nil {
+		targetRole = *target.Role
+	}
+
+	return outranksRole(c, targetRole)
+}
+
+func outranksRole(c *gin.Context, role string) bool {
+	value, ok := c.Get(utils.UserKey)
+
+	if !ok {
+		return false
+	}
+
+	actor := value.(models.User)
+	actorRole := models.RoleUser
+
+	if actor.Role != nil {
+		actorRole = *actor.Role
+	}
+
+	return models.Outranks(actorRole, role)
+}
+
+func isCurrentUser(c *gin.Context, id primitive.ObjectID) bool {
+	uid, err := utils.ExtractTokenID(c)
+
+	return err == nil && *uid == id
+}
@@ -16,15 +16,20 @@ func RegisterRoutes(r *gin.Engine, db *mongo.Database, client *mongo.Client) {
 		Client: client,
 	}
 
+	LoadProviders()
+
+	r.GET("/.well-known/jwks.json", h.GetJWKS)
+
 	routes := r.Group("/api/v1/auth")
 	routes.POST("/register", h.Register)
 	routes.POST("/login", h.Login)
-	routes.GET("/login/facebook", h.InitFacebookLogin)
-	routes.POST("/login/facebook/mobile", h.LoginWithFacebookMobile)
-	routes.GET("/facebook/callback", h.HandleFacebookLogin)
-	routes.GET("/login/google", h.InitGoogleLogin)
-	routes.POST("/login/google/mobile", h.LoginWithGoogleMobile)
-	routes.GET("/google/callback", h.HandleGoogleLogin)
+	routes.POST("/login/email", h.RequestEmailLogin)
+	routes.POST("/login/email/verify", h.VerifyEmailLogin)
+	routes.GET("/login/:provider", h.InitOAuthLogin)
+	routes.POST("/login/:provider/mobile", h.LoginWithOAuthMobile)
+	routes.GET("/:provider/callback", h.HandleOAuthLogin)
 	routes.POST("/verify/email", h.VerifyEmail)
 	routes.POST("/verify/resendCode", h.ResendCode)
+	routes.POST("/reactivate", h.RequestReactivation)
+	routes.POST("/reactivate/verify", h.VerifyReactivation)
 }
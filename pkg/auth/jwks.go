@@ -0,0 +1,13 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+)
+
+func (h handler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{"keys": utils.JWKS()})
+}
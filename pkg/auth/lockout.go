@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const lockedOutMessage = "too many failed login attempts, please try again later"
+
+func isLockedOut(user models.User) bool {
+	return user.LockedUntil != nil && user.LockedUntil.After(time.Now())
+}
+
+func registerFailedLogin(coll *mongo.Collection, user models.User) error {
+	maxAttempts, err := strconv.Atoi(os.Getenv("LOGIN_MAX_ATTEMPTS"))
+
+	if err != nil {
+		maxAttempts = 5
+	}
+
+	lockoutMinutes, err := strconv.Atoi(os.Getenv("LOGIN_LOCKOUT_MINUTES"))
+
+	if err != nil {
+		lockoutMinutes = 15
+	}
+
+	now := time.Now()
+	lockExpired := bson.D{{Key: "$and", Value: bson.A{
+		bson.D{{Key: "$eq", Value: bson.A{bson.D{{Key: "$type", Value: "$locked_until"}}, "date"}}},
+		bson.D{{Key: "$lte", Value: bson.A{"$locked_until", now}}},
+	}}}
+
+	reached := bson.D{{Key: "$gte", Value: bson.A{"$failed_logins", maxAttempts}}}
+
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "failed_logins", Value: bson.D{{Key: "$cond", Value: bson.A{
+				lockExpired,
+				1,
+				bson.D{{Key: "$add", Value: bson.A{bson.D{{Key: "$ifNull", Value: bson.A{"$failed_logins", 0}}}, 1}}},
+			}}}},
+		}}},
+		{{Key: "$set", Value: bson.D{
+			{Key: "locked_until", Value: bson.D{{Key: "$cond", Value: bson.A{
+				reached,
+				now.Add(time.Minute * time.Duration(lockoutMinutes)),
+				bson.D{{Key: "$cond", Value: bson.A{lockExpired, "$$REMOVE", "$locked_until"}}},
+			}}}},
+			{Key: "failed_logins", Value: bson.D{{Key: "$cond", Value: bson.A{reached, 0, "$failed_logins"}}}},
+		}}},
+	}
+
+	_, err = coll.UpdateByID(context.TODO(), user.Id, update)
+	return err
+}
+
+func resetFailedLogins(coll *mongo.Collection, user models.User) error {
+	if user.FailedLogins == nil && user.LockedUntil == nil {
+		return nil
+	}
+
+	update := bson.D{
+		{
+			Key: "$unset",
+			Value: bson.D{
+				{Key: "failed_logins", Value: ""},
+				{Key: "locked_until", Value: ""},
+			},
+		},
+	}
+
+	_, err := coll.UpdateByID(context.TODO(), user.Id, update)
+	return err
+}
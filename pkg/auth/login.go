@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Bryan-an/tasker-backend/pkg/common/lifecycle"
 	"github.com/Bryan-an/tasker-backend/pkg/common/models"
 	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
 	"github.com/gin-gonic/gin"
@@ -61,7 +62,17 @@ func (h handler) Login(c *gin.Context) {
 		return
 	}
 
-	if correct := verifyPassword(*input.Password, *u.Password); !correct {
+	if isLockedOut(u) {
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": lockedOutMessage})
+		return
+	}
+
+	if u.Password == nil || !verifyPassword(*input.Password, *u.Password) {
+		if err := registerFailedLogin(usersCollection, u); err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
 		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
 			"error": "user or password incorrect",
 		})
@@ -69,6 +80,11 @@ func (h handler) Login(c *gin.Context) {
 		return
 	}
 
+	if err := resetFailedLogins(usersCollection, u); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
 	token, err := utils.GenerateToken(u.Id.Hex())
 
 	if err != nil {
@@ -84,7 +100,12 @@ func verifyPassword(password, hash string) bool {
 	return err == nil
 }
 
-func SignInUser(details models.UserDetails, db *mongo.Database, client *mongo.Client) (string, error) {
+var (
+	errUnverifiedEmail = errors.New("the email address was not verified by the login provider")
+	errLockedOut       = errors.New(lockedOutMessage)
+)
+
+func SignInUser(provider string, details models.UserDetails, db *mongo.Database, client *mongo.Client) (string, error) {
 	if details == (models.UserDetails{}) {
 		return "", errors.New("user details can't be empty")
 	}
@@ -99,6 +120,14 @@ func SignInUser(details models.UserDetails, db *mongo.Database, client *mongo.Cl
 
 	usersCollection := db.Collection("users")
 
+	if !details.EmailVerified {
+		return signInLinkedUser(usersCollection, provider, details)
+	}
+
+	if err := activateCreatedUser(context.TODO(), db, details.Email); err != nil {
+		return "", errors.New("error occurred while logging in user")
+	}
+
 	filter := bson.D{
 		{Key: "email", Value: details.Email},
 		{Key: "status", Value: "active"},
@@ -108,8 +137,24 @@ func SignInUser(details models.UserDetails, db *mongo.Database, client *mongo.Cl
 	var token string
 	var tokenErr error
 
-	if err := usersCollection.FindOne(context.TODO(), filter).Decode(&user); err != nil {
+	err := mongo.ErrNoDocuments
+
+	if details.ID != "" {
+		err = usersCollection.FindOne(context.TODO(), identityFilter(provider, details.ID)).Decode(&user)
+	}
+
+	if err == mongo.ErrNoDocuments {
+		err = usersCollection.FindOne(context.TODO(), filter).Decode(&user)
+	}
+
+	if err != nil {
 		if err == mongo.ErrNoDocuments {
+			emailFilter := bson.D{{Key: "email", Value: details.Email}}
+
+			if count, err := usersCollection.CountDocuments(context.TODO(), emailFilter); err != nil || count > 0 {
+				return "", errors.New("the account for this email address is not active")
+			}
+
 			wc := writeconcern.Majority()
 			txnOptions := options.Transaction().SetWriteConcern(wc)
 			session, err := client.StartSession()
@@ -121,17 +166,18 @@ func SignInUser(details models.UserDetails, db *mongo.Database, client *mongo.Cl
 			defer session.EndSession(context.TODO())
 
 			_, err = session.WithTransaction(context.TODO(), func(ctx mongo.SessionContext) (interface{}, error) {
-				role := "user"
+				role := models.RoleUser
 				status := "active"
 				now := time.Now()
 
 				u := models.User{
-					Name:      &details.Name,
-					Email:     &details.Email,
-					Role:      &role,
-					Status:    &status,
-					CreatedAt: &now,
-					UpdatedAt: &now,
+					Name:       &details.Name,
+					Email:      &details.Email,
+					Role:       &role,
+					Status:     &status,
+					Identities: &[]models.Identity{{Provider: &provider, Subject: &details.ID}},
+					CreatedAt:  &now,
+					UpdatedAt:  &now,
 				}
 
 				req, err := usersCollection.InsertOne(ctx, u)
@@ -145,6 +191,7 @@ func SignInUser(details models.UserDetails, db *mongo.Database, client *mongo.Cl
 				emailNotifications := false
 				mobileNotifications := true
 				theme := "light"
+				version := int64(1)
 
 				s := models.Settings{
 					UserId: &uid,
@@ -153,6 +200,7 @@ func SignInUser(details models.UserDetails, db *mongo.Database, client *mongo.Cl
 						Mobile: &mobileNotifications,
 					},
 					Theme:     &theme,
+					Version:   &version,
 					CreatedAt: &now,
 					UpdatedAt: &now,
 				}
@@ -175,6 +223,14 @@ func SignInUser(details models.UserDetails, db *mongo.Database, client *mongo.Cl
 		}
 
 	} else {
+		if isLockedOut(user) {
+			return "", errLockedOut
+		}
+
+		if err := linkIdentity(usersCollection, user, provider, details.ID); err != nil {
+			return "", errors.New("error occurred while logging in user")
+		}
+
 		token, tokenErr = utils.GenerateToken(user.Id.Hex())
 	}
 
@@ -184,3 +240,110 @@ func SignInUser(details models.UserDetails, db *mongo.Database, client *mongo.Cl
 
 	return token, nil
 }
+
+func signInLinkedUser(coll *mongo.Collection, provider string, details models.UserDetails) (string, error) {
+	if details.ID == "" {
+		return "", errUnverifiedEmail
+	}
+
+	var user models.User
+
+	if err := coll.FindOne(context.TODO(), identityFilter(provider, details.ID)).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", errUnverifiedEmail
+		}
+
+		return "", errors.New("error occurred while logging in user")
+	}
+
+	if isLockedOut(user) {
+		return "", errLockedOut
+	}
+
+	token, err := utils.GenerateToken(user.Id.Hex())
+
+	if err != nil {
+		return "", errors.New("error occurred while generating auth token")
+	}
+
+	return token, nil
+}
+
+func identityFilter(provider string, subject string) bson.D {
+	return bson.D{
+		{Key: "identities", Value: bson.D{{Key: "$elemMatch", Value: bson.D{
+			{Key: "provider", Value: provider},
+			{Key: "subject", Value: subject},
+		}}}},
+		{Key: "status", Value: "active"},
+	}
+}
+
+func linkIdentity(coll *mongo.Collection, user models.User, provider string, subject string) error {
+	if subject == "" {
+		return nil
+	}
+
+	if user.Identities != nil {
+		for _, identity := range *user.Identities {
+			if *identity.Provider == provider && *identity.Subject == subject {
+				return nil
+			}
+		}
+	}
+
+	update := bson.D{
+		{
+			Key: "$addToSet",
+			Value: bson.D{
+				{Key: "identities", Value: models.Identity{Provider: &provider, Subject: &subject}},
+			},
+		},
+		{
+			Key: "$set",
+			Value: bson.D{
+				{Key: "updated_at", Value: time.Now()},
+			},
+		},
+	}
+
+	_, err := coll.UpdateByID(context.TODO(), user.Id, update)
+	return err
+}
+
+func activateCreatedUser(ctx context.Context, db *mongo.Database, email string) error {
+	now := time.Now()
+
+	filter := bson.D{
+		{Key: "email", Value: email},
+		{Key: "status", Value: lifecycle.StatusCreated},
+	}
+
+	update := bson.D{
+		{
+			Key: "$set",
+			Value: bson.D{
+				{Key: "status", Value: lifecycle.StatusActive},
+				{Key: "status_changed_at", Value: now},
+				{Key: "updated_at", Value: now},
+			},
+		},
+		{
+			Key: "$unset",
+			Value: bson.D{
+				{Key: "password", Value: ""},
+				{Key: "failed_logins", Value: ""},
+				{Key: "locked_until", Value: ""},
+			},
+		},
+	}
+
+	result, err := db.Collection("users").UpdateOne(ctx, filter, update)
+
+	if err != nil || result.ModifiedCount == 0 {
+		return err
+	}
+
+	_, err = db.Collection("verifications").DeleteMany(ctx, bson.D{{Key: "email", Value: email}})
+	return err
+}
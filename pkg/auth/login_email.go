@@ -0,0 +1,302 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+type emailLoginInput struct {
+	Email    *string `json:"email" binding:"required,email"`
+	Platform *string `json:"platform" binding:"omitempty,oneof=web android"`
+}
+
+type emailLoginVerifyInput struct {
+	Email *string `json:"email" binding:"required,email"`
+	Token *string `json:"token" binding:"required_without=Code"`
+	Code  *string `json:"code" binding:"required_without=Token"`
+}
+
+const emailLoginMessage = "if an account exists for this email, a login link has been sent to it"
+
+func (h handler) RequestEmailLogin(c *gin.Context) {
+	var input emailLoginInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	user, err := findEmailLoginUser(h.DB, *input.Email)
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusOK, gin.H{"message": emailLoginMessage})
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if isLockedOut(user) {
+		c.JSON(http.StatusOK, gin.H{"message": emailLoginMessage})
+		return
+	}
+
+	token, err := utils.GetRandomString(32)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	code, err := utils.GetOTPToken(6)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	lifespan, err := strconv.Atoi(os.Getenv("MAGIC_LINK_EXPIRATION"))
+
+	if err != nil {
+		lifespan = 900
+	}
+
+	tokenHash := utils.HashToken(token)
+	codeHash := utils.HashToken(code)
+	now := time.Now()
+	expiresAt := now.Add(time.Second * time.Duration(lifespan))
+
+	link := models.LoginLink{
+		Email:     input.Email,
+		TokenHash: &tokenHash,
+		CodeHash:  &codeHash,
+		ExpiresAt: &expiresAt,
+		CreatedAt: &now,
+	}
+
+	linksCollection := h.DB.Collection("login_links")
+	linksFilter := bson.D{{Key: "email", Value: input.Email}}
+
+	if _, err := linksCollection.DeleteMany(context.TODO(), linksFilter); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if _, err := linksCollection.InsertOne(context.TODO(), link); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	loginURL := getLoginLinkURL(input.Platform, *input.Email, token)
+
+	err = utils.SendEmail(
+		*input.Email,
+		"Tasker - Login link",
+		"<p>Click <a href=\""+loginURL+"\">here</a> to log in to Tasker.</p>"+
+			"<p>If the link doesn't work, use this code instead: <b>"+code+"</b></p>",
+	)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": emailLoginMessage})
+}
+
+func (h handler) VerifyEmailLogin(c *gin.Context) {
+	var input emailLoginVerifyInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	const linkNotFoundMessage = "login link not found for user with email '%s'"
+	linksCollection := h.DB.Collection("login_links")
+	linksFilter := bson.D{{Key: "email", Value: input.Email}}
+	var link models.LoginLink
+
+	if err := linksCollection.FindOne(context.TODO(), linksFilter).Decode(&link); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf(linkNotFoundMessage, *input.Email),
+			})
+
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	user, err := findEmailLoginUser(h.DB, *input.Email)
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf(linkNotFoundMessage, *input.Email),
+			})
+
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	usersCollection := h.DB.Collection("users")
+
+	if isLockedOut(user) {
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": lockedOutMessage})
+		return
+	}
+
+	if link.ExpiresAt.Before(time.Now()) {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{
+			"error": "login link has expired, please request a new one",
+		})
+
+		return
+	}
+
+	if !matchesLoginLink(link, input) {
+		if err := registerFailedLogin(usersCollection, user); err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{
+			"error": "login link or code provided is invalid",
+		})
+
+		return
+	}
+
+	wc := writeconcern.Majority()
+	txnOptions := options.Transaction().SetWriteConcern(wc)
+	session, err := h.Client.StartSession()
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	defer session.EndSession(context.TODO())
+
+	_, err = session.WithTransaction(
+		context.TODO(),
+		func(ctx mongo.SessionContext) (interface{}, error) {
+			result, err := linksCollection.DeleteOne(ctx, bson.D{{Key: "_id", Value: link.Id}})
+
+			if err != nil {
+				return nil, err
+			}
+
+			if result.DeletedCount == 0 {
+				return nil, errors.New("login link has already been used")
+			}
+
+			if *user.Status != "created" {
+				return nil, nil
+			}
+
+			return nil, activateCreatedUser(ctx, h.DB, *user.Email)
+		},
+		txnOptions)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := resetFailedLogins(usersCollection, user); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	token, err := utils.GenerateToken(user.Id.Hex())
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": token})
+}
+
+func findEmailLoginUser(db *mongo.Database, email string) (models.User, error) {
+	filter := bson.D{
+		{Key: "email", Value: email},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{"active", "created"}}}},
+	}
+
+	var user models.User
+	err := db.Collection("users").FindOne(context.TODO(), filter).Decode(&user)
+
+	return user, err
+}
+
+func matchesLoginLink(link models.LoginLink, input emailLoginVerifyInput) bool {
+	if input.Token != nil {
+		hash := utils.HashToken(*input.Token)
+		return subtle.ConstantTimeCompare([]byte(hash), []byte(*link.TokenHash)) == 1
+	}
+
+	if link.CodeHash == nil {
+		return false
+	}
+
+	hash := utils.HashToken(*input.Code)
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(*link.CodeHash)) == 1
+}
+
+func getLoginLinkURL(platform *string, email string, token string) string {
+	base := os.Getenv("MAGIC_LINK_URL")
+
+	if platform != nil && *platform == "android" {
+		if androidBase := os.Getenv("MAGIC_LINK_ANDROID_URL"); androidBase != "" {
+			base = androidBase
+		}
+	}
+
+	params := url.Values{}
+	params.Set("email", email)
+	params.Set("token", token)
+
+	return base + "?" + params.Encode()
+}
@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type loginInputMobile struct {
+	Token *string `json:"token" binding:"required"`
+}
+
+func getProvider(c *gin.Context) *Provider {
+	name := c.Param("provider")
+
+	if _, ok := providers[strings.ToLower(name)]; !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("login provider '%s' is not supported", name),
+		})
+
+		return nil
+	}
+
+	provider, err := GetProvider(name)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return nil
+	}
+
+	return provider
+}
+
+func (h handler) InitOAuthLogin(c *gin.Context) {
+	provider := getProvider(c)
+
+	if provider == nil {
+		return
+	}
+
+	url := provider.AuthCodeURL(GetRandomOAuthStateString())
+	http.Redirect(c.Writer, c.Request, url, http.StatusTemporaryRedirect)
+}
+
+func (h handler) HandleOAuthLogin(c *gin.Context) {
+	provider := getProvider(c)
+
+	if provider == nil {
+		return
+	}
+
+	var state = c.Request.FormValue("state")
+	var code = c.Request.FormValue("code")
+
+	if state != GetRandomOAuthStateString() || code == "" {
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{"error": "error while logging in user"},
+		)
+
+		return
+	}
+
+	token, err := provider.Exchange(context.TODO(), code)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	details, err := provider.GetUserInfo(token.AccessToken)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	authToken, err := SignInUser(provider.Name(), details, h.DB, h.Client)
+
+	if err == errUnverifiedEmail {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err == errLockedOut {
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": authToken})
+}
+
+func (h handler) LoginWithOAuthMobile(c *gin.Context) {
+	provider := getProvider(c)
+
+	if provider == nil {
+		return
+	}
+
+	var input loginInputMobile
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	details, err := provider.GetUserInfo(*input.Token)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	authToken, err := SignInUser(provider.Name(), details, h.DB, h.Client)
+
+	if err == errUnverifiedEmail {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err == errLockedOut {
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": authToken})
+}
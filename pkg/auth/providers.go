@@ -0,0 +1,372 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"golang.org/x/oauth2"
+	facebookOAuth "golang.org/x/oauth2/facebook"
+	"golang.org/x/oauth2/google"
+)
+
+var providerClient = &http.Client{Timeout: 10 * time.Second}
+
+type ClaimMapping struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	EmailVerified string `json:"email_verified"`
+}
+
+type ProviderConfig struct {
+	Name         string       `json:"name"`
+	ClientID     string       `json:"client_id"`
+	ClientSecret string       `json:"client_secret"`
+	RedirectURL  string       `json:"redirect_url"`
+	DiscoveryURL string       `json:"discovery_url"`
+	AuthURL      string       `json:"auth_url"`
+	TokenURL     string       `json:"token_url"`
+	UserInfoURL  string       `json:"userinfo_url"`
+	Scopes       []string     `json:"scopes"`
+	Claims       ClaimMapping `json:"claims"`
+	TrustEmail   bool         `json:"trust_email"`
+}
+
+type Provider struct {
+	config   ProviderConfig
+	mu       sync.Mutex
+	oauth    *oauth2.Config
+	userInfo string
+}
+
+type discoveryDocument struct {
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+var builtinProviders = map[string]ProviderConfig{
+	"google": {
+		Name:        "google",
+		AuthURL:     google.Endpoint.AuthURL,
+		TokenURL:    google.Endpoint.TokenURL,
+		UserInfoURL: "https://www.googleapis.com/oauth2/v2/userinfo",
+		Scopes: []string{
+			"https://www.googleapis.com/auth/userinfo.email",
+			"https://www.googleapis.com/auth/userinfo.profile",
+		},
+		Claims: ClaimMapping{ID: "id", Name: "name", Email: "email", EmailVerified: "verified_email"},
+	},
+	"facebook": {
+		Name:        "facebook",
+		AuthURL:     facebookOAuth.Endpoint.AuthURL,
+		TokenURL:    facebookOAuth.Endpoint.TokenURL,
+		UserInfoURL: "https://graph.facebook.com/me?fields=id,name,email",
+		Scopes:      []string{"email"},
+		Claims:      ClaimMapping{ID: "id", Name: "name", Email: "email"},
+		TrustEmail:  true,
+	},
+}
+
+var providers = map[string]*Provider{}
+
+func LoadProviders() {
+	var configs []ProviderConfig
+
+	if path := os.Getenv("OAUTH_PROVIDERS_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+
+		if err != nil {
+			log.Fatal("Error reading OAuth providers file", err)
+		}
+
+		if err := json.Unmarshal(data, &configs); err != nil {
+			log.Fatal("Error parsing OAuth providers file", err)
+		}
+	}
+
+	names := os.Getenv("OAUTH_PROVIDERS")
+
+	if names == "" && len(configs) == 0 {
+		names = "google,facebook"
+	}
+
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(strings.ToLower(name)); name != "" {
+			configs = append(configs, providerConfigFromEnv(name))
+		}
+	}
+
+	for _, config := range configs {
+		config = withDefaults(config)
+
+		if config.ClientID == "" {
+			log.Printf("OAuth provider '%s' has no client id, skipping", config.Name)
+			continue
+		}
+
+		providers[config.Name] = &Provider{config: config}
+	}
+}
+
+func GetProvider(name string) (*Provider, error) {
+	p, ok := providers[strings.ToLower(name)]
+
+	if !ok {
+		return nil, fmt.Errorf("login provider '%s' is not supported", name)
+	}
+
+	if err := p.init(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func providerConfigFromEnv(name string) ProviderConfig {
+	prefix := strings.ToUpper(name) + "_"
+
+	getenv := func(key string) string {
+		if v := os.Getenv("OAUTH_" + prefix + key); v != "" {
+			return v
+		}
+
+		return os.Getenv(prefix + key)
+	}
+
+	config := ProviderConfig{
+		Name:         name,
+		ClientID:     getenv("CLIENT_ID"),
+		ClientSecret: getenv("CLIENT_SECRET"),
+		RedirectURL:  getenv("REDIRECT_URL"),
+		DiscoveryURL: getenv("DISCOVERY_URL"),
+		AuthURL:      getenv("AUTH_URL"),
+		TokenURL:     getenv("TOKEN_URL"),
+		UserInfoURL:  getenv("USERINFO_URL"),
+		Claims: ClaimMapping{
+			ID:            getenv("CLAIM_ID"),
+			Name:          getenv("CLAIM_NAME"),
+			Email:         getenv("CLAIM_EMAIL"),
+			EmailVerified: getenv("CLAIM_EMAIL_VERIFIED"),
+		},
+		TrustEmail: getenv("TRUST_EMAIL") == "true",
+	}
+
+	if scopes := getenv("SCOPES"); scopes != "" {
+		config.Scopes = strings.Split(scopes, ",")
+	}
+
+	return config
+}
+
+func withDefaults(config ProviderConfig) ProviderConfig {
+	config.Name = strings.ToLower(config.Name)
+	builtin, ok := builtinProviders[config.Name]
+
+	if !ok {
+		builtin = ProviderConfig{
+			Scopes: []string{"openid", "email", "profile"},
+			Claims: ClaimMapping{ID: "sub", Name: "name", Email: "email", EmailVerified: "email_verified"},
+		}
+	}
+
+	if config.DiscoveryURL == "" {
+		if config.AuthURL == "" {
+			config.AuthURL = builtin.AuthURL
+		}
+
+		if config.TokenURL == "" {
+			config.TokenURL = builtin.TokenURL
+		}
+
+		if config.UserInfoURL == "" {
+			config.UserInfoURL = builtin.UserInfoURL
+		}
+	}
+
+	if len(config.Scopes) == 0 {
+		config.Scopes = builtin.Scopes
+	}
+
+	if config.Claims.ID == "" {
+		config.Claims.ID = builtin.Claims.ID
+	}
+
+	if config.Claims.Name == "" {
+		config.Claims.Name = builtin.Claims.Name
+	}
+
+	if config.Claims.Email == "" {
+		config.Claims.Email = builtin.Claims.Email
+	}
+
+	if config.Claims.EmailVerified == "" {
+		config.Claims.EmailVerified = builtin.Claims.EmailVerified
+	}
+
+	if ok && builtin.TrustEmail {
+		config.TrustEmail = true
+	}
+
+	return config
+}
+
+func (p *Provider) init() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.oauth != nil {
+		return nil
+	}
+
+	authURL := p.config.AuthURL
+	tokenURL := p.config.TokenURL
+	userInfo := p.config.UserInfoURL
+
+	if p.config.DiscoveryURL != "" {
+		doc, err := discover(p.config.DiscoveryURL)
+
+		if err != nil {
+			return err
+		}
+
+		authURL = doc.AuthorizationEndpoint
+		tokenURL = doc.TokenEndpoint
+
+		if userInfo == "" {
+			userInfo = doc.UserInfoEndpoint
+		}
+	}
+
+	if authURL == "" || tokenURL == "" || userInfo == "" {
+		return fmt.Errorf("login provider '%s' is not fully configured", p.config.Name)
+	}
+
+	p.userInfo = userInfo
+	p.oauth = &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Endpoint:     oauth2.Endpoint{AuthURL: authURL, TokenURL: tokenURL},
+		Scopes:       p.config.Scopes,
+	}
+
+	return nil
+}
+
+func discover(url string) (discoveryDocument, error) {
+	var doc discoveryDocument
+
+	if !strings.Contains(url, "/.well-known/") {
+		url = strings.TrimSuffix(url, "/") + "/.well-known/openid-configuration"
+	}
+
+	res, err := providerClient.Get(url)
+
+	if err != nil {
+		return doc, fmt.Errorf("error occurred while fetching OpenID configuration: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return doc, fmt.Errorf("OpenID configuration request failed with status %d", res.StatusCode)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&doc); err != nil {
+		return doc, fmt.Errorf("error occurred while decoding OpenID configuration: %w", err)
+	}
+
+	return doc, nil
+}
+
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+func (p *Provider) AuthCodeURL(state string) string {
+	return p.oauth.AuthCodeURL(state)
+}
+
+func (p *Provider) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	return p.oauth.Exchange(context.WithValue(ctx, oauth2.HTTPClient, providerClient), code)
+}
+
+func (p *Provider) GetUserInfo(token string) (models.UserDetails, error) {
+	errMsg := fmt.Sprintf("error ocurred while getting user info from %s", p.config.Name)
+	req, err := http.NewRequest("GET", p.userInfo, nil)
+
+	if err != nil {
+		return models.UserDetails{}, errors.New(errMsg)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	res, err := providerClient.Do(req)
+
+	if err != nil {
+		return models.UserDetails{}, errors.New(errMsg)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return models.UserDetails{}, errors.New(errMsg)
+	}
+
+	var claims map[string]interface{}
+
+	if err := json.NewDecoder(res.Body).Decode(&claims); err != nil {
+		return models.UserDetails{}, errors.New(errMsg)
+	}
+
+	verified := p.config.TrustEmail
+
+	if !verified && p.config.Claims.EmailVerified != "" {
+		verified = claimString(claims, p.config.Claims.EmailVerified) == "true"
+	}
+
+	return models.UserDetails{
+		ID:            claimString(claims, p.config.Claims.ID),
+		Name:          claimString(claims, p.config.Claims.Name),
+		Email:         claimString(claims, p.config.Claims.Email),
+		EmailVerified: verified,
+	}, nil
+}
+
+func claimString(claims map[string]interface{}, path string) string {
+	var value interface{} = claims
+
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+
+		if !ok {
+			return ""
+		}
+
+		value = m[key]
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func GetRandomOAuthStateString() string {
+	return os.Getenv("OAUTH_STATE_STRING")
+}
@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/lifecycle"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+type reactivateInput struct {
+	Email *string `json:"email" binding:"required,email"`
+}
+
+func (h handler) RequestReactivation(c *gin.Context) {
+	var input reactivateInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	if _, err := findReactivableUser(h.DB, *input.Email); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("no account can be reactivated with email '%s'", *input.Email),
+			})
+
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	otp, err := utils.GetOTPToken(6)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	lifespan, err := strconv.Atoi(os.Getenv("EMAIL_VERIFICATION_CODE_EXPIRATION"))
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	expiresAt := time.Now().Add(time.Second * time.Duration(lifespan))
+	purpose := models.VerificationPurposeReactivation
+
+	data := models.VerificationData{
+		Email:     input.Email,
+		Code:      &otp,
+		Purpose:   &purpose,
+		ExpiresAt: &expiresAt,
+	}
+
+	coll := h.DB.Collection("verifications")
+
+	verificationsFilter := bson.D{
+		{Key: "email", Value: input.Email},
+		{Key: "purpose", Value: purpose},
+	}
+
+	if _, err := coll.DeleteMany(context.TODO(), verificationsFilter); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if _, err := coll.InsertOne(context.TODO(), data); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	err = utils.SendEmail(
+		*input.Email,
+		"Tasker - Account reactivation",
+		"<p>This is your code to reactivate your Tasker account: <b>"+otp+"</b></p>",
+	)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "please check your email for the reactivation code",
+	})
+}
+
+func (h handler) VerifyReactivation(c *gin.Context) {
+	var data models.VerificationData
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	verificationsColl := h.DB.Collection("verifications")
+
+	verificationsFilter := bson.D{
+		{Key: "email", Value: data.Email},
+		{Key: "purpose", Value: models.VerificationPurposeReactivation},
+	}
+
+	var actualData models.VerificationData
+
+	if err := verificationsColl.FindOne(context.TODO(), verificationsFilter).Decode(&actualData); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("reactivation code not found for user with email '%s'", *data.Email),
+			})
+
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if status, err := verifyData(verificationsColl, verificationsFilter, actualData, data); err != nil {
+		if status == http.StatusInternalServerError {
+			c.AbortWithError(status, err)
+		} else {
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+		}
+
+		return
+	}
+
+	user, err := findReactivableUser(h.DB, *data.Email)
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("no account can be reactivated with email '%s'", *data.Email),
+			})
+
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	wc := writeconcern.Majority()
+	txnOptions := options.Transaction().SetWriteConcern(wc)
+	session, err := h.Client.StartSession()
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	defer session.EndSession(context.TODO())
+
+	_, err = session.WithTransaction(
+		context.TODO(),
+		func(ctx mongo.SessionContext) (interface{}, error) {
+			usersColl := h.DB.Collection("users")
+			filter := bson.D{{Key: "_id", Value: user.Id}}
+			result, err := lifecycle.Transition(ctx, usersColl, filter, lifecycle.StatusActive)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if result.MatchedCount == 0 {
+				return nil, errors.New("account can't be reactivated")
+			}
+
+			_, err = verificationsColl.DeleteMany(ctx, verificationsFilter)
+			return nil, err
+		},
+		txnOptions)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	token, err := utils.GenerateToken(user.Id.Hex())
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": token})
+}
+
+func findReactivableUser(db *mongo.Database, email string) (models.User, error) {
+	cutoff := bson.D{{Key: "$gt", Value: time.Now().Add(-lifecycle.ReactivationGracePeriod())}}
+
+	filter := bson.D{
+		{Key: "email", Value: email},
+		{Key: "status", Value: lifecycle.StatusUnsubscribed},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "status_changed_at", Value: cutoff}},
+			bson.D{
+				{Key: "status_changed_at", Value: nil},
+				{Key: "updated_at", Value: cutoff},
+			},
+		}},
+	}
+
+	var user models.User
+	err := db.Collection("users").FindOne(context.TODO(), filter).Decode(&user)
+
+	return user, err
+}
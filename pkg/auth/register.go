@@ -2,15 +2,13 @@ package auth
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
 	"net/http"
 	"os"
 	"strconv"
 	"time"
 
-	gomail "gopkg.in/mail.v2"
-
+	"github.com/Bryan-an/tasker-backend/pkg/common/lifecycle"
 	"github.com/Bryan-an/tasker-backend/pkg/common/models"
 	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
 	"github.com/gin-gonic/gin"
@@ -64,8 +62,8 @@ func (h handler) Register(c *gin.Context) {
 
 	input.Password = &hash
 
-	role := "user"
-	status := "created"
+	role := models.RoleUser
+	status := lifecycle.StatusCreated
 	now := time.Now()
 
 	u := models.User{
@@ -104,6 +102,7 @@ func (h handler) Register(c *gin.Context) {
 			emailNotifications := false
 			mobileNotifications := true
 			theme := "light"
+			version := int64(1)
 			now := time.Now()
 
 			s := models.Settings{
@@ -113,6 +112,7 @@ func (h handler) Register(c *gin.Context) {
 					Mobile: &mobileNotifications,
 				},
 				Theme:     &theme,
+				Version:   &version,
 				CreatedAt: &now,
 				UpdatedAt: &now,
 			}
@@ -145,15 +145,26 @@ func (h handler) Register(c *gin.Context) {
 
 func validateUser(h handler, c *gin.Context, input registerInput) error {
 	usersCollection := h.DB.Collection("users")
+	filter := bson.D{{Key: "email", Value: input.Email}}
+	var user models.User
+	err := usersCollection.FindOne(context.TODO(), filter).Decode(&user)
 
-	filter := bson.D{
-		{Key: "email", Value: input.Email},
-		{Key: "status", Value: "active"},
+	if err != nil && err != mongo.ErrNoDocuments {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return err
 	}
 
-	var user models.User
+	exists := err == nil
+
+	if exists && *user.Status == lifecycle.StatusUnsubscribed {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"error": "this account was unsubscribed, please reactivate it instead",
+		})
 
-	if err := usersCollection.FindOne(context.TODO(), filter).Decode(&user); err == nil {
+		return errors.New("this account was unsubscribed")
+	}
+
+	if exists && *user.Status != lifecycle.StatusCreated && *user.Status != lifecycle.StatusPendingDeletion {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
 			"error": "this email address is already in use",
 		})
@@ -178,30 +189,52 @@ func validateUser(h handler, c *gin.Context, input registerInput) error {
 		return err
 	}
 
+	if exists {
+		if err := releaseEmail(h, user); err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return err
+		}
+	}
+
 	return nil
 }
 
+func releaseEmail(h handler, user models.User) error {
+	usersCollection := h.DB.Collection("users")
+	filter := bson.D{{Key: "_id", Value: user.Id}}
+	result, err := lifecycle.Transition(context.TODO(), usersCollection, filter, lifecycle.StatusDeleted)
+
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("this email address is already in use")
+	}
+
+	verificationsFilter := bson.D{{Key: "email", Value: user.Email}}
+
+	if _, err := h.DB.Collection("verifications").DeleteMany(context.TODO(), verificationsFilter); err != nil {
+		return err
+	}
+
+	return lifecycle.Anonymize(context.TODO(), usersCollection, *user.Id)
+}
+
 func SendVerificationEmail(h handler, c *gin.Context, user models.User, msc mongo.SessionContext) error {
-	m := gomail.NewMessage()
-	from := os.Getenv("SENDER_EMAIL")
-	to := *user.Email
-	password := os.Getenv("SENDER_PASSWORD")
-	host := "smtp.gmail.com"
-	port := 587
 	otp, err := utils.GetOTPToken(6)
 
 	if err != nil {
 		return err
 	}
 
-	m.SetHeader("From", from)
-	m.SetHeader("To", to)
-	m.SetHeader("Subject", "Tasker - Email code verification")
-	m.SetBody("text/html", "<p>This is your email verification code for Tasker: <b>"+otp+"</b></p>")
-	d := gomail.NewDialer(host, port, from, password)
-	d.TLSConfig = &tls.Config{InsecureSkipVerify: true}
+	err = utils.SendEmail(
+		*user.Email,
+		"Tasker - Email code verification",
+		"<p>This is your email verification code for Tasker: <b>"+otp+"</b></p>",
+	)
 
-	if err := d.DialAndSend(m); err != nil {
+	if err != nil {
 		return err
 	}
 
@@ -213,9 +246,12 @@ func SendVerificationEmail(h handler, c *gin.Context, user models.User, msc mong
 
 	expiresAt := time.Now().Add(time.Second * time.Duration(lifespan))
 
+	purpose := models.VerificationPurposeEmail
+
 	data := &models.VerificationData{
 		Email:     user.Email,
 		Code:      &otp,
+		Purpose:   &purpose,
 		ExpiresAt: &expiresAt,
 	}
 
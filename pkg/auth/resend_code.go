@@ -50,7 +50,11 @@ func (h handler) ResendCode(c *gin.Context) {
 		context.TODO(),
 		func(ctx mongo.SessionContext) (interface{}, error) {
 			coll := h.DB.Collection("verifications")
-			verificationsFilter := bson.D{{Key: "email", Value: input.Email}}
+			verificationsFilter := bson.D{
+				{Key: "email", Value: input.Email},
+				{Key: "purpose", Value: bson.D{{Key: "$in", Value: bson.A{nil, models.VerificationPurposeEmail}}}},
+			}
+
 			result, err := coll.DeleteOne(ctx, verificationsFilter)
 
 			if err != nil {
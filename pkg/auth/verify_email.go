@@ -2,9 +2,12 @@ package auth
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/Bryan-an/tasker-backend/pkg/common/models"
@@ -34,7 +37,11 @@ func (h handler) VerifyEmail(c *gin.Context) {
 	}
 
 	verificationsColl := h.DB.Collection("verifications")
-	verificationsFilter := bson.D{{Key: "email", Value: data.Email}}
+	verificationsFilter := bson.D{
+		{Key: "email", Value: data.Email},
+		{Key: "purpose", Value: bson.D{{Key: "$in", Value: bson.A{nil, models.VerificationPurposeEmail}}}},
+	}
+
 	var actualData models.VerificationData
 	const verificationNotFoundMessage = "verification code not found for user with email '%s'"
 
@@ -51,10 +58,13 @@ func (h handler) VerifyEmail(c *gin.Context) {
 		return
 	}
 
-	valid, err := verifyData(actualData, data)
+	if status, err := verifyData(verificationsColl, verificationsFilter, actualData, data); err != nil {
+		if status == http.StatusInternalServerError {
+			c.AbortWithError(status, err)
+		} else {
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+		}
 
-	if !valid {
-		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -84,6 +94,8 @@ func (h handler) VerifyEmail(c *gin.Context) {
 					Key: "$set",
 					Value: bson.D{
 						{Key: "status", Value: "active"},
+						{Key: "status_changed_at", Value: time.Now()},
+						{Key: "updated_at", Value: time.Now()},
 					},
 				},
 			}
@@ -131,14 +143,49 @@ func (h handler) VerifyEmail(c *gin.Context) {
 	})
 }
 
-func verifyData(actualData models.VerificationData, data models.VerificationData) (bool, error) {
-	if *actualData.Code != *data.Code {
-		return false, errors.New("verification code provided is invalid, please look in your email for the code")
+var errTooManyCodeAttempts = errors.New("too many invalid codes, please request a new code")
+
+func verificationMaxAttempts() int {
+	attempts, err := strconv.Atoi(os.Getenv("VERIFICATION_MAX_ATTEMPTS"))
+
+	if err != nil || attempts < 1 {
+		attempts = 5
+	}
+
+	return attempts
+}
+
+func verifyData(coll *mongo.Collection, filter bson.D, actualData models.VerificationData, data models.VerificationData) (int, error) {
+	if actualData.Attempts != nil && *actualData.Attempts >= verificationMaxAttempts() {
+		if _, err := coll.DeleteMany(context.TODO(), filter); err != nil {
+			return http.StatusInternalServerError, err
+		}
+
+		return http.StatusTooManyRequests, errTooManyCodeAttempts
+	}
+
+	if subtle.ConstantTimeCompare([]byte(*actualData.Code), []byte(*data.Code)) != 1 {
+		update := bson.D{{Key: "$inc", Value: bson.D{{Key: "attempts", Value: 1}}}}
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+		if err := coll.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&actualData); err != nil && err != mongo.ErrNoDocuments {
+			return http.StatusInternalServerError, err
+		}
+
+		if actualData.Attempts != nil && *actualData.Attempts >= verificationMaxAttempts() {
+			if _, err := coll.DeleteMany(context.TODO(), filter); err != nil {
+				return http.StatusInternalServerError, err
+			}
+
+			return http.StatusTooManyRequests, errTooManyCodeAttempts
+		}
+
+		return http.StatusNotAcceptable, errors.New("verification code provided is invalid, please look in your email for the code")
 	}
 
 	if actualData.ExpiresAt.Before(time.Now()) {
-		return false, errors.New("verification code has expired, please try generating a new code")
+		return http.StatusNotAcceptable, errors.New("verification code has expired, please try generating a new code")
 	}
 
-	return true, nil
+	return http.StatusOK, nil
 }
@@ -44,6 +44,140 @@ func Connect() *mongo.Client {
 		log.Fatal(err)
 	}
 
+	_, err = database.Collection("access_tokens").Indexes().CreateOne(
+		context.TODO(),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = database.Collection("task_history").Indexes().CreateMany(
+		context.TODO(),
+		[]mongo.IndexModel{
+			{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "created_at", Value: -1}}},
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		},
+	)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = database.Collection("tasks").Indexes().CreateMany(
+		context.TODO(),
+		[]mongo.IndexModel{
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "updated_at", Value: 1}}},
+			{
+				Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "client_id", Value: 1}},
+				Options: options.Index().
+					SetUnique(true).
+					SetPartialFilterExpression(bson.D{{Key: "client_id", Value: bson.D{{Key: "$exists", Value: true}}}}),
+			},
+		},
+	)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = database.Collection("task_tombstones").Indexes().CreateOne(
+		context.TODO(),
+		mongo.IndexModel{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "deleted_at", Value: 1}},
+		},
+	)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = database.Collection("webhook_deliveries").Indexes().CreateMany(
+		context.TODO(),
+		[]mongo.IndexModel{
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+			{Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		},
+	)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = database.Collection("labels").Indexes().CreateOne(
+		context.TODO(),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = database.Collection("calendar_feeds").Indexes().CreateMany(
+		context.TODO(),
+		[]mongo.IndexModel{
+			{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+	)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = database.Collection("time_entries").Indexes().CreateMany(
+		context.TODO(),
+		[]mongo.IndexModel{
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "started_at", Value: -1}}},
+			{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "started_at", Value: -1}}},
+			{
+				Keys: bson.D{{Key: "user_id", Value: 1}},
+				Options: options.Index().
+					SetUnique(true).
+					SetPartialFilterExpression(bson.D{{Key: "running", Value: true}}),
+			},
+		},
+	)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = database.Collection("stream_tickets").Indexes().CreateMany(
+		context.TODO(),
+		[]mongo.IndexModel{
+			{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+	)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, name := range []string{"tasks", "settings"} {
+		result, err := database.Collection(name).UpdateMany(
+			context.TODO(),
+			bson.D{{Key: "version", Value: nil}},
+			bson.D{{Key: "$set", Value: bson.D{{Key: "version", Value: int64(0)}}}},
+		)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if result.ModifiedCount > 0 {
+			log.Printf("%d %s document(s) backfilled with a version", result.ModifiedCount, name)
+		}
+	}
+
 	log.Println("Database connected")
 
 	return client
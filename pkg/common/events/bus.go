@@ -0,0 +1,265 @@
+package events
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	TaskPurged      = "task.purged"
+	SettingsUpdated = "settings.updated"
+	Resync          = "resync"
+
+	defaultBufferSize = 100
+	defaultBufferTTL  = 30 * time.Minute
+)
+
+type Event struct {
+	Id        string      `json:"id"`
+	Type      string      `json:"type"`
+	Data      interface{} `json:"data,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	seq       uint64
+}
+
+type TaskChange struct {
+	TaskId  primitive.ObjectID   `json:"task_id"`
+	Action  string               `json:"action"`
+	Changes []models.FieldChange `json:"changes"`
+}
+
+type TasksPurged struct {
+	TaskIds []primitive.ObjectID `json:"task_ids"`
+}
+
+type Subscription struct {
+	Events <-chan Event
+	Replay []Event
+	Resync bool
+	cancel func()
+}
+
+type Bus struct {
+	mu          sync.Mutex
+	boot        string
+	seq         uint64
+	size        int
+	subscribers map[primitive.ObjectID]map[chan Event]struct{}
+	buffers     map[primitive.ObjectID][]Event
+	dropped     map[primitive.ObjectID]uint64
+	evicted     uint64
+	ttl         time.Duration
+	pruned      time.Time
+	listeners   []Listener
+}
+
+type Listener func(userId primitive.ObjectID, event Event)
+
+var Default = NewBus(defaultBufferSize, defaultBufferTTL)
+
+func NewBus(size int, ttl time.Duration) *Bus {
+	return &Bus{
+		boot:        strconv.FormatInt(time.Now().UnixNano(), 36),
+		size:        size,
+		ttl:         ttl,
+		pruned:      time.Now(),
+		subscribers: map[primitive.ObjectID]map[chan Event]struct{}{},
+		buffers:     map[primitive.ObjectID][]Event{},
+		dropped:     map[primitive.ObjectID]uint64{},
+	}
+}
+
+func Configure() {
+	Default.Configure(bufferSize(), bufferTTL())
+}
+
+func Publish(userId primitive.ObjectID, eventType string, data interface{}) {
+	Default.Publish(userId, eventType, data)
+}
+
+func Subscribe(userId primitive.ObjectID, lastEventId string) *Subscription {
+	return Default.Subscribe(userId, lastEventId)
+}
+
+func Listen(listener Listener) {
+	Default.Listen(listener)
+}
+
+func TaskEvent(action string) string {
+	return "task." + action
+}
+
+func (b *Bus) Publish(userId primitive.ObjectID, eventType string, data interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	b.prune(time.Now())
+
+	event := Event{
+		Id:        fmt.Sprintf("%s-%d", b.boot, b.seq),
+		Type:      eventType,
+		Data:      data,
+		CreatedAt: time.Now(),
+		seq:       b.seq,
+	}
+
+	buffer := append(b.buffers[userId], event)
+
+	if len(buffer) > b.size {
+		b.dropped[userId] = buffer[len(buffer)-b.size-1].seq
+		buffer = buffer[len(buffer)-b.size:]
+	}
+
+	b.buffers[userId] = buffer
+
+	for ch := range b.subscribers[userId] {
+		select {
+		case ch <- event:
+		default:
+			delete(b.subscribers[userId], ch)
+			close(ch)
+		}
+	}
+
+	for _, listener := range b.listeners {
+		go listener(userId, event)
+	}
+}
+
+func (b *Bus) Configure(size int, ttl time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.size = size
+	b.ttl = ttl
+}
+
+func (b *Bus) Listen(listener Listener) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.listeners = append(b.listeners, listener)
+}
+
+func (b *Bus) Subscribe(userId primitive.ObjectID, lastEventId string) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Event, 64)
+	sub := &Subscription{Events: ch}
+
+	if lastEventId != "" {
+		sub.Replay, sub.Resync = b.replay(userId, lastEventId)
+	}
+
+	if b.subscribers[userId] == nil {
+		b.subscribers[userId] = map[chan Event]struct{}{}
+	}
+
+	b.subscribers[userId][ch] = struct{}{}
+
+	sub.cancel = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if _, ok := b.subscribers[userId][ch]; !ok {
+			return
+		}
+
+		delete(b.subscribers[userId], ch)
+		close(ch)
+
+		if len(b.subscribers[userId]) == 0 {
+			delete(b.subscribers, userId)
+		}
+	}
+
+	return sub
+}
+
+func (b *Bus) replay(userId primitive.ObjectID, lastEventId string) ([]Event, bool) {
+	boot, seqString, ok := strings.Cut(lastEventId, "-")
+
+	if !ok || boot != b.boot {
+		return nil, true
+	}
+
+	last, err := strconv.ParseUint(seqString, 10, 64)
+
+	if err != nil || last < b.dropped[userId] {
+		return nil, true
+	}
+
+	if _, ok := b.buffers[userId]; !ok && last < b.evicted {
+		return nil, true
+	}
+
+	replay := []Event{}
+
+	for _, event := range b.buffers[userId] {
+		if event.seq > last {
+			replay = append(replay, event)
+		}
+	}
+
+	return replay, false
+}
+
+func (b *Bus) prune(now time.Time) {
+	if now.Sub(b.pruned) < b.ttl/2 {
+		return
+	}
+
+	b.pruned = now
+
+	for userId, buffer := range b.buffers {
+		if len(b.subscribers[userId]) > 0 || len(buffer) == 0 {
+			continue
+		}
+
+		last := buffer[len(buffer)-1]
+
+		if now.Sub(last.CreatedAt) < b.ttl {
+			continue
+		}
+
+		if last.seq > b.evicted {
+			b.evicted = last.seq
+		}
+
+		delete(b.buffers, userId)
+		delete(b.dropped, userId)
+	}
+}
+
+func (s *Subscription) Close() {
+	s.cancel()
+}
+
+func bufferSize() int {
+	size, err := strconv.Atoi(os.Getenv("EVENTS_BUFFER_SIZE"))
+
+	if err != nil || size < 1 {
+		size = defaultBufferSize
+	}
+
+	return size
+}
+
+func bufferTTL() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("EVENTS_BUFFER_TTL_MINUTES"))
+
+	if err != nil || minutes < 1 {
+		return defaultBufferTTL
+	}
+
+	return time.Minute * time.Duration(minutes)
+}
@@ -0,0 +1,213 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	Collection           = "task_history"
+	TombstonesCollection = "task_tombstones"
+)
+
+var ignoredFields = map[string]bool{
+	"_id":        true,
+	"user_id":    true,
+	"created_at": true,
+	"updated_at": true,
+	"version":    true,
+}
+
+func Diff(before bson.M, after bson.M) []models.FieldChange {
+	changes := []models.FieldChange{}
+	fields := make([]string, 0, len(after))
+
+	for field := range after {
+		if !ignoredFields[field] {
+			fields = append(fields, field)
+		}
+	}
+
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		if !equal(before[field], after[field]) {
+			changes = append(changes, models.FieldChange{
+				Field: field,
+				Old:   before[field],
+				New:   after[field],
+			})
+		}
+	}
+
+	return changes
+}
+
+func ToMap(v interface{}) (bson.M, error) {
+	data, err := bson.Marshal(v)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var m bson.M
+
+	if err := bson.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func equal(a interface{}, b interface{}) bool {
+	if a == nil || b == nil {
+		return isNil(a) && isNil(b)
+	}
+
+	ra, err := bson.Marshal(bson.M{"v": a})
+
+	if err != nil {
+		return false
+	}
+
+	rb, err := bson.Marshal(bson.M{"v": b})
+
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(ra, rb)
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	raw, err := bson.Marshal(bson.M{"v": v})
+
+	if err != nil {
+		return false
+	}
+
+	null, _ := bson.Marshal(bson.M{"v": nil})
+
+	return bytes.Equal(raw, null)
+}
+
+func Record(ctx context.Context, db *mongo.Database, entry models.TaskHistory) error {
+	if entry.Changes == nil {
+		entry.Changes = []models.FieldChange{}
+	}
+
+	if entry.CreatedAt == nil {
+		now := time.Now()
+		entry.CreatedAt = &now
+	}
+
+	_, err := db.Collection(Collection).InsertOne(ctx, entry)
+	return err
+}
+
+func RecordMany(ctx context.Context, db *mongo.Database, entries []models.TaskHistory) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(entries))
+	now := time.Now()
+
+	for _, entry := range entries {
+		if entry.Changes == nil {
+			entry.Changes = []models.FieldChange{}
+		}
+
+		if entry.CreatedAt == nil {
+			entry.CreatedAt = &now
+		}
+
+		docs = append(docs, entry)
+	}
+
+	_, err := db.Collection(Collection).InsertMany(ctx, docs)
+	return err
+}
+
+func UndoExpiration() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("UNDO_EXPIRATION_MINUTES"))
+
+	if err != nil {
+		minutes = 15
+	}
+
+	return time.Minute * time.Duration(minutes)
+}
+
+func NewUndoToken(entries []models.TaskHistory) (string, error) {
+	token, err := utils.GetRandomString(32)
+
+	if err != nil {
+		return "", err
+	}
+
+	hash := utils.HashToken(token)
+	expiresAt := time.Now().Add(UndoExpiration())
+
+	for i := range entries {
+		entries[i].UndoTokenHash = &hash
+		entries[i].UndoExpiresAt = &expiresAt
+	}
+
+	return token, nil
+}
+
+func TombstoneRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("SYNC_TOMBSTONE_DAYS"))
+
+	if err != nil {
+		days = 90
+	}
+
+	return time.Hour * 24 * time.Duration(days)
+}
+
+func RecordTombstones(ctx context.Context, db *mongo.Database, tombstones []models.Tombstone) error {
+	if len(tombstones) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(tombstones))
+	now := time.Now()
+
+	for _, tombstone := range tombstones {
+		if tombstone.DeletedAt == nil {
+			tombstone.DeletedAt = &now
+		}
+
+		docs = append(docs, tombstone)
+	}
+
+	_, err := db.Collection(TombstonesCollection).InsertMany(ctx, docs)
+	return err
+}
+
+func DeleteForTasks(ctx context.Context, db *mongo.Database, taskIds []primitive.ObjectID) error {
+	if len(taskIds) == 0 {
+		return nil
+	}
+
+	filter := bson.D{{Key: "task_id", Value: bson.D{{Key: "$in", Value: taskIds}}}}
+	_, err := db.Collection(Collection).DeleteMany(ctx, filter)
+
+	return err
+}
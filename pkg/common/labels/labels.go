@@ -0,0 +1,60 @@
+package labels
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const Collection = "labels"
+
+func Key(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func Ensure(ctx context.Context, db *mongo.Database, uid primitive.ObjectID, names []string) error {
+	seen := map[string]bool{}
+	writes := []mongo.WriteModel{}
+	now := time.Now()
+
+	for _, name := range names {
+		key := Key(name)
+
+		if key == "" || seen[key] {
+			continue
+		}
+
+		seen[key] = true
+
+		filter := bson.D{
+			{Key: "user_id", Value: uid},
+			{Key: "key", Value: key},
+		}
+
+		update := bson.D{{Key: "$setOnInsert", Value: bson.D{
+			{Key: "name", Value: strings.TrimSpace(name)},
+			{Key: "sort_order", Value: 0},
+			{Key: "created_at", Value: now},
+			{Key: "updated_at", Value: now},
+		}}}
+
+		writes = append(writes, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true))
+	}
+
+	if len(writes) == 0 {
+		return nil
+	}
+
+	_, err := db.Collection(Collection).BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+
+	return err
+}
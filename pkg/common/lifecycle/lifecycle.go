@@ -0,0 +1,117 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	StatusCreated         = "created"
+	StatusActive          = "active"
+	StatusSuspended       = "suspended"
+	StatusUnsubscribed    = "unsubscribed"
+	StatusPendingDeletion = "pending_deletion"
+	StatusDeleted         = "deleted"
+)
+
+var transitions = map[string][]string{
+	StatusCreated:         {StatusActive, StatusDeleted},
+	StatusActive:          {StatusSuspended, StatusUnsubscribed, StatusPendingDeletion},
+	StatusSuspended:       {StatusActive, StatusPendingDeletion},
+	StatusUnsubscribed:    {StatusActive, StatusPendingDeletion},
+	StatusPendingDeletion: {StatusDeleted},
+	StatusDeleted:         {},
+}
+
+func CanTransition(from string, to string) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+func AllowedFrom(to string) []string {
+	from := []string{}
+
+	for status := range transitions {
+		if CanTransition(status, to) {
+			from = append(from, status)
+		}
+	}
+
+	return from
+}
+
+func Transition(ctx context.Context, coll *mongo.Collection, filter bson.D, to string) (*mongo.UpdateResult, error) {
+	now := time.Now()
+
+	filter = append(filter, bson.E{
+		Key: "status", Value: bson.D{{Key: "$in", Value: AllowedFrom(to)}},
+	})
+
+	update := bson.D{
+		{
+			Key: "$set",
+			Value: bson.D{
+				{Key: "status", Value: to},
+				{Key: "status_changed_at", Value: now},
+				{Key: "updated_at", Value: now},
+			},
+		},
+	}
+
+	return coll.UpdateMany(ctx, filter, update)
+}
+
+func Anonymize(ctx context.Context, coll *mongo.Collection, id primitive.ObjectID) error {
+	update := bson.D{
+		{
+			Key: "$set",
+			Value: bson.D{
+				{Key: "email", Value: fmt.Sprintf("%s@deleted.invalid", id.Hex())},
+				{Key: "updated_at", Value: time.Now()},
+			},
+		},
+		{
+			Key: "$unset",
+			Value: bson.D{
+				{Key: "name", Value: ""},
+				{Key: "password", Value: ""},
+				{Key: "identities", Value: ""},
+			},
+		},
+	}
+
+	_, err := coll.UpdateByID(ctx, id, update)
+	return err
+}
+
+func ReactivationGracePeriod() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("ACCOUNT_REACTIVATION_GRACE_DAYS"))
+
+	if err != nil {
+		days = 30
+	}
+
+	return time.Hour * 24 * time.Duration(days)
+}
+
+func UnverifiedAccountTTL() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("UNVERIFIED_ACCOUNT_TTL_HOURS"))
+
+	if err != nil {
+		hours = 72
+	}
+
+	return time.Hour * time.Duration(hours)
+}
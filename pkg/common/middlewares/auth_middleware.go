@@ -1,19 +1,107 @@
 package middlewares
 
 import (
+	"context"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/Bryan-an/tasker-backend/pkg/common/lifecycle"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
 	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func JwtAuthMiddleware() gin.HandlerFunc {
+func JwtAuthMiddleware(db *mongo.Database) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if err := utils.TokenValid(c); err != nil {
+		tokenString := utils.ExtractToken(c)
+
+		if strings.HasPrefix(tokenString, utils.AccessTokenPrefix) {
+			if err := authenticateAccessToken(c, db, tokenString); err != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+				return
+			}
+		}
+
+		uid, err := utils.ExtractTokenID(c)
+
+		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 			return
 		}
 
+		filter := bson.D{
+			{Key: "_id", Value: uid},
+			{Key: "status", Value: lifecycle.StatusActive},
+		}
+
+		count, err := db.Collection("users").CountDocuments(context.TODO(), filter)
+
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		if count == 0 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		c.Set(utils.UserIdKey, uid)
+		c.Next()
+	}
+}
+
+func RequireScopes(readScope string, writeScope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope := writeScope
+
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			scope = readScope
+		}
+
+		if !utils.HasScope(c, scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "token is missing the '" + scope + "' scope",
+			})
+
+			return
+		}
+
 		c.Next()
 	}
 }
+
+func authenticateAccessToken(c *gin.Context, db *mongo.Database, tokenString string) error {
+	coll := db.Collection("access_tokens")
+	now := time.Now()
+
+	filter := bson.D{
+		{Key: "hash", Value: utils.HashToken(tokenString)},
+		{Key: "status", Value: "active"},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "expires_at", Value: nil}},
+			bson.D{{Key: "expires_at", Value: bson.D{{Key: "$gt", Value: now}}}},
+		}},
+	}
+
+	var token models.AccessToken
+
+	if err := coll.FindOne(context.TODO(), filter).Decode(&token); err != nil {
+		return err
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > time.Minute {
+		update := bson.D{{Key: "$set", Value: bson.D{{Key: "last_used_at", Value: now}}}}
+
+		if _, err := coll.UpdateByID(context.TODO(), token.Id, update); err != nil {
+			return err
+		}
+	}
+
+	c.Set(utils.UserIdKey, token.UserId)
+	c.Set(utils.ScopesKey, *token.Scopes)
+	return nil
+}
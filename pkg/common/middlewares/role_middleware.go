@@ -0,0 +1,73 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func LoadUser(db *mongo.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uid, err := utils.ExtractTokenID(c)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		var user models.User
+		opts := options.FindOne().SetProjection(bson.D{{Key: "password", Value: 0}})
+
+		filter := bson.D{
+			{Key: "_id", Value: uid},
+			{Key: "status", Value: "active"},
+		}
+
+		if err := db.Collection("users").FindOne(context.TODO(), filter, opts).Decode(&user); err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+				return
+			}
+
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.Set(utils.UserKey, user)
+		c.Next()
+	}
+}
+
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get(utils.UserKey)
+
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		user := value.(models.User)
+		role := models.RoleUser
+
+		if user.Role != nil {
+			role = *user.Role
+		}
+
+		if !models.HasPermission(role, permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "you don't have permission to perform this action",
+			})
+
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AccessToken struct {
+	Id         *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserId     *primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Name       *string             `json:"name,omitempty" bson:"name,omitempty"`
+	Prefix     *string             `json:"prefix,omitempty" bson:"prefix,omitempty"`
+	Hash       *string             `json:"hash,omitempty" bson:"hash,omitempty"`
+	Scopes     *[]string           `json:"scopes,omitempty" bson:"scopes,omitempty"`
+	Status     *string             `json:"status,omitempty" bson:"status,omitempty"`
+	ExpiresAt  *time.Time          `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	LastUsedAt *time.Time          `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+	CreatedAt  *time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt  *time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CalendarFeed struct {
+	Id         *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserId     *primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Prefix     *string             `json:"prefix,omitempty" bson:"prefix,omitempty"`
+	TokenHash  *string             `json:"-" bson:"token_hash,omitempty"`
+	LastUsedAt *time.Time          `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+	CreatedAt  *time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt  *time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
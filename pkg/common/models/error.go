@@ -0,0 +1,6 @@
+package models
+
+type ErrorMsg struct {
+	Field   string `json:"field" bson:"field"`
+	Message string `json:"message" bson:"message"`
+}
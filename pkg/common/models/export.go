@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Export struct {
+	Id        *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserId    *primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Status    *string             `json:"status,omitempty" bson:"status,omitempty"`
+	TokenHash *string             `json:"-" bson:"token_hash,omitempty"`
+	FilePath  *string             `json:"-" bson:"file_path,omitempty"`
+	Error     *string             `json:"error,omitempty" bson:"error,omitempty"`
+	ExpiresAt *time.Time          `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	CreatedAt *time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt *time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ImportFormatICS = "ics"
+	ImportFormatCSV = "csv"
+)
+
+const (
+	ImportRowReady   = "ready"
+	ImportRowCreated = "created"
+	ImportRowSkipped = "skipped"
+	ImportRowInvalid = "invalid"
+)
+
+type Import struct {
+	Id        *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserId    *primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Format    *string             `json:"format,omitempty" bson:"format,omitempty"`
+	FileName  *string             `json:"file_name,omitempty" bson:"file_name,omitempty"`
+	FilePath  *string             `json:"-" bson:"file_path,omitempty"`
+	Mapping   map[string]string   `json:"mapping,omitempty" bson:"mapping,omitempty"`
+	DryRun    *bool               `json:"dry_run,omitempty" bson:"dry_run,omitempty"`
+	Status    *string             `json:"status,omitempty" bson:"status,omitempty"`
+	Error     *string             `json:"error,omitempty" bson:"error,omitempty"`
+	Summary   *ImportSummary      `json:"summary,omitempty" bson:"summary,omitempty"`
+	Rows      []ImportRow         `json:"rows,omitempty" bson:"rows,omitempty"`
+	CreatedAt *time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt *time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+type ImportSummary struct {
+	Total   int `json:"total" bson:"total"`
+	Valid   int `json:"valid" bson:"valid"`
+	Created int `json:"created" bson:"created"`
+	Skipped int `json:"skipped" bson:"skipped"`
+	Invalid int `json:"invalid" bson:"invalid"`
+}
+
+type ImportRow struct {
+	Row    int                 `json:"row" bson:"row"`
+	Status string              `json:"status" bson:"status"`
+	Title  *string             `json:"title,omitempty" bson:"title,omitempty"`
+	TaskId *primitive.ObjectID `json:"task_id,omitempty" bson:"task_id,omitempty"`
+	Reason *string             `json:"reason,omitempty" bson:"reason,omitempty"`
+	Errors []ErrorMsg          `json:"errors,omitempty" bson:"errors,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Label struct {
+	Id         *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserId     *primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Name       *string             `json:"name,omitempty" bson:"name,omitempty"`
+	Key        *string             `json:"-" bson:"key,omitempty"`
+	Color      *string             `json:"color,omitempty" bson:"color,omitempty"`
+	SortOrder  *int                `json:"sort_order,omitempty" bson:"sort_order,omitempty"`
+	UsageCount *int64              `json:"usage_count,omitempty" bson:"-"`
+	CreatedAt  *time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt  *time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type LoginLink struct {
+	Id        *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Email     *string             `json:"email,omitempty" bson:"email,omitempty"`
+	TokenHash *string             `json:"token_hash,omitempty" bson:"token_hash,omitempty"`
+	CodeHash  *string             `json:"code_hash,omitempty" bson:"code_hash,omitempty"`
+	ExpiresAt *time.Time          `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	CreatedAt *time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PurgeAudit struct {
+	Id        *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Kind      *string             `json:"kind,omitempty" bson:"kind,omitempty"`
+	UserId    *primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	DryRun    *bool               `json:"dry_run,omitempty" bson:"dry_run,omitempty"`
+	Deleted   map[string]int64    `json:"deleted,omitempty" bson:"deleted,omitempty"`
+	CreatedAt *time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
@@ -0,0 +1,51 @@
+package models
+
+const (
+	RoleUser    = "user"
+	RoleSupport = "support"
+	RoleAdmin   = "admin"
+)
+
+const (
+	PermissionReadUsers       = "users.read"
+	PermissionChangeStatus    = "users.status"
+	PermissionChangeRole      = "users.role"
+	PermissionReadUserTasks   = "users.tasks.read"
+	PermissionManageRetention = "retention.manage"
+)
+
+var RolePermissions = map[string][]string{
+	RoleUser: {},
+	RoleSupport: {
+		PermissionReadUsers,
+		PermissionChangeStatus,
+		PermissionReadUserTasks,
+	},
+	RoleAdmin: {
+		PermissionReadUsers,
+		PermissionChangeStatus,
+		PermissionChangeRole,
+		PermissionReadUserTasks,
+		PermissionManageRetention,
+	},
+}
+
+var roleRanks = map[string]int{
+	RoleUser:    0,
+	RoleSupport: 1,
+	RoleAdmin:   2,
+}
+
+func Outranks(role string, other string) bool {
+	return roleRanks[role] > roleRanks[other]
+}
+
+func HasPermission(role string, permission string) bool {
+	for _, p := range RolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
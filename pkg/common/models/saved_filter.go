@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TaskFilter struct {
+	Priority   string     `json:"priority,omitempty" bson:"priority,omitempty"`
+	Complexity string     `json:"complexity,omitempty" bson:"complexity,omitempty"`
+	Labels     string     `json:"labels,omitempty" bson:"labels,omitempty"`
+	Done       string     `json:"done,omitempty" bson:"done,omitempty"`
+	Remind     string     `json:"remind,omitempty" bson:"remind,omitempty"`
+	DateFrom   *time.Time `json:"date_from,omitempty" bson:"date_from,omitempty"`
+	DateTo     *time.Time `json:"date_to,omitempty" bson:"date_to,omitempty"`
+	Search     string     `json:"search,omitempty" bson:"search,omitempty"`
+	Query      string     `json:"query,omitempty" bson:"query,omitempty"`
+}
+
+type SavedFilter struct {
+	Id        *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserId    *primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Name      *string             `json:"name,omitempty" bson:"name,omitempty"`
+	Filter    *TaskFilter         `json:"filter,omitempty" bson:"filter,omitempty"`
+	Sort      *string             `json:"sort,omitempty" bson:"sort,omitempty"`
+	Order     *string             `json:"order,omitempty" bson:"order,omitempty"`
+	Pinned    *bool               `json:"pinned,omitempty" bson:"pinned,omitempty"`
+	Count     *int64              `json:"count,omitempty" bson:"-"`
+	CreatedAt *time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt *time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
@@ -12,10 +12,13 @@ type Notification struct {
 }
 
 type Settings struct {
-	Id            *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
-	UserId        *primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
-	Notifications *Notification       `json:"notifications,omitempty" bson:"notifications,omitempty"`
-	Theme         *string             `json:"theme,omitempty" bson:"theme,omitempty"`
-	CreatedAt     *time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
-	UpdatedAt     *time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+	Id              *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserId          *primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Notifications   *Notification       `json:"notifications,omitempty" bson:"notifications,omitempty"`
+	Theme           *string             `json:"theme,omitempty" bson:"theme,omitempty"`
+	AutoArchiveDays *int                `json:"auto_archive_days,omitempty" bson:"auto_archive_days,omitempty"`
+	Timezone        *string             `json:"timezone,omitempty" bson:"timezone,omitempty"`
+	Version         *int64              `json:"version" bson:"version,omitempty"`
+	CreatedAt       *time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt       *time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
 }
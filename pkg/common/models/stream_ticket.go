@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type StreamTicket struct {
+	Id        *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserId    *primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Hash      *string             `json:"-" bson:"hash,omitempty"`
+	Scopes    *[]string           `json:"scopes,omitempty" bson:"scopes,omitempty"`
+	ExpiresAt *time.Time          `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	CreatedAt *time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
@@ -9,6 +9,7 @@ import (
 type Task struct {
 	Id          *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
 	UserId      *primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	ClientId    *string             `json:"client_id,omitempty" bson:"client_id,omitempty"`
 	Title       *string             `json:"title,omitempty" bson:"title,omitempty"`
 	Description *string             `json:"description,omitempty" bson:"description,omitempty"`
 	Labels      *[]string           `json:"labels,omitempty" bson:"labels,omitempty"`
@@ -20,6 +21,12 @@ type Task struct {
 	Done        *bool               `json:"done,omitempty" bson:"done,omitempty"`
 	Remind      *bool               `json:"remind,omitempty" bson:"remind,omitempty"`
 	Status      *string             `json:"status,omitempty" bson:"status,omitempty"`
+	DoneAt      *time.Time          `json:"done_at,omitempty" bson:"done_at,omitempty"`
+	ArchivedAt  *time.Time          `json:"archived_at,omitempty" bson:"archived_at,omitempty"`
+	DeletedAt   *time.Time          `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	TrashedFrom *string             `json:"trashed_from,omitempty" bson:"trashed_from,omitempty"`
+	Version     *int64              `json:"version" bson:"version,omitempty"`
+	Tracked     *int64              `json:"tracked_seconds,omitempty" bson:"-"`
 	CreatedAt   *time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
 	UpdatedAt   *time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
 }
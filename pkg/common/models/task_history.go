@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	TaskActionCreated    = "created"
+	TaskActionUpdated    = "updated"
+	TaskActionReplaced   = "replaced"
+	TaskActionDeleted    = "deleted"
+	TaskActionRestored   = "restored"
+	TaskActionArchived   = "archived"
+	TaskActionUnarchived = "unarchived"
+	TaskActionReverted   = "reverted"
+)
+
+type FieldChange struct {
+	Field string      `json:"field" bson:"field"`
+	Old   interface{} `json:"old" bson:"old"`
+	New   interface{} `json:"new" bson:"new"`
+}
+
+type TaskHistory struct {
+	Id            *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	TaskId        *primitive.ObjectID `json:"task_id,omitempty" bson:"task_id,omitempty"`
+	UserId        *primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	ActorId       *primitive.ObjectID `json:"actor_id,omitempty" bson:"actor_id,omitempty"`
+	Action        *string             `json:"action,omitempty" bson:"action,omitempty"`
+	Source        *string             `json:"source,omitempty" bson:"source,omitempty"`
+	Changes       []FieldChange       `json:"changes" bson:"changes"`
+	RevertOf      *primitive.ObjectID `json:"revert_of,omitempty" bson:"revert_of,omitempty"`
+	UndoTokenHash *string             `json:"-" bson:"undo_token_hash,omitempty"`
+	UndoExpiresAt *time.Time          `json:"undo_expires_at,omitempty" bson:"undo_expires_at,omitempty"`
+	UndoneAt      *time.Time          `json:"undone_at,omitempty" bson:"undone_at,omitempty"`
+	CreatedAt     *time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
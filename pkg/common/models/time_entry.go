@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	TimeEntrySourceTimer  = "timer"
+	TimeEntrySourceManual = "manual"
+)
+
+type TimeEntry struct {
+	Id        *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserId    *primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	TaskId    *primitive.ObjectID `json:"task_id,omitempty" bson:"task_id,omitempty"`
+	StartedAt *time.Time          `json:"started_at,omitempty" bson:"started_at,omitempty"`
+	EndedAt   *time.Time          `json:"ended_at,omitempty" bson:"ended_at,omitempty"`
+	Running   *bool               `json:"running,omitempty" bson:"running,omitempty"`
+	Seconds   *int64              `json:"seconds,omitempty" bson:"seconds,omitempty"`
+	Note      *string             `json:"note,omitempty" bson:"note,omitempty"`
+	Source    *string             `json:"source,omitempty" bson:"source,omitempty"`
+	CreatedAt *time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt *time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
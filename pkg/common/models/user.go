@@ -7,18 +7,28 @@ import (
 )
 
 type User struct {
-	Id        *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
-	Name      *string             `json:"name,omitempty" bson:"name,omitempty"`
-	Email     *string             `json:"email,omitempty" bson:"email,omitempty"`
-	Password  *string             `json:"password,omitempty" bson:"password,omitempty"`
-	Role      *string             `json:"role,omitempty" bson:"role,omitempty"`
-	Status    *string             `json:"status,omitempty" bson:"status,omitempty"`
-	CreatedAt *time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
-	UpdatedAt *time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+	Id              *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Name            *string             `json:"name,omitempty" bson:"name,omitempty"`
+	Email           *string             `json:"email,omitempty" bson:"email,omitempty"`
+	Password        *string             `json:"password,omitempty" bson:"password,omitempty"`
+	Role            *string             `json:"role,omitempty" bson:"role,omitempty"`
+	Status          *string             `json:"status,omitempty" bson:"status,omitempty"`
+	StatusChangedAt *time.Time          `json:"status_changed_at,omitempty" bson:"status_changed_at,omitempty"`
+	Identities      *[]Identity         `json:"identities,omitempty" bson:"identities,omitempty"`
+	FailedLogins    *int                `json:"failed_logins,omitempty" bson:"failed_logins,omitempty"`
+	LockedUntil     *time.Time          `json:"locked_until,omitempty" bson:"locked_until,omitempty"`
+	CreatedAt       *time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt       *time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+type Identity struct {
+	Provider *string `json:"provider,omitempty" bson:"provider,omitempty"`
+	Subject  *string `json:"subject,omitempty" bson:"subject,omitempty"`
 }
 
 type UserDetails struct {
-	ID    string
-	Name  string
-	Email string
+	ID            string
+	Name          string
+	Email         string
+	EmailVerified bool
 }
@@ -1,9 +1,22 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	VerificationPurposeEmail        = "email_verification"
+	VerificationPurposeEmailChange  = "email_change"
+	VerificationPurposeReactivation = "reactivation"
+)
 
 type VerificationData struct {
-	Email     *string    `json:"email,omitempty" bson:"email,omitempty" binding:"required,email"`
-	Code      *string    `json:"code,omitempty" bson:"code,omitempty" binding:"required"`
-	ExpiresAt *time.Time `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	Email     *string             `json:"email,omitempty" bson:"email,omitempty" binding:"required,email"`
+	Code      *string             `json:"code,omitempty" bson:"code,omitempty" binding:"required"`
+	UserId    *primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Purpose   *string             `json:"purpose,omitempty" bson:"purpose,omitempty"`
+	ExpiresAt *time.Time          `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	Attempts  *int                `json:"attempts,omitempty" bson:"attempts,omitempty"`
 }
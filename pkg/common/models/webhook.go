@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySending   = "sending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+type Webhook struct {
+	Id                  *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserId              *primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	URL                 *string             `json:"url,omitempty" bson:"url,omitempty"`
+	Events              *[]string           `json:"events,omitempty" bson:"events,omitempty"`
+	Secret              *string             `json:"-" bson:"secret,omitempty"`
+	Active              *bool               `json:"active,omitempty" bson:"active,omitempty"`
+	ConsecutiveFailures *int                `json:"consecutive_failures,omitempty" bson:"consecutive_failures,omitempty"`
+	DisabledAt          *time.Time          `json:"disabled_at,omitempty" bson:"disabled_at,omitempty"`
+	CreatedAt           *time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt           *time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+type WebhookDelivery struct {
+	Id            *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	WebhookId     *primitive.ObjectID `json:"webhook_id,omitempty" bson:"webhook_id,omitempty"`
+	UserId        *primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Event         *string             `json:"event,omitempty" bson:"event,omitempty"`
+	Payload       *string             `json:"payload,omitempty" bson:"payload,omitempty"`
+	Status        *string             `json:"status,omitempty" bson:"status,omitempty"`
+	Attempts      *int                `json:"attempts,omitempty" bson:"attempts,omitempty"`
+	ResponseCode  *int                `json:"response_code,omitempty" bson:"response_code,omitempty"`
+	ResponseBody  *string             `json:"response_body,omitempty" bson:"response_body,omitempty"`
+	Error         *string             `json:"error,omitempty" bson:"error,omitempty"`
+	RedeliveryOf  *primitive.ObjectID `json:"redelivery_of,omitempty" bson:"redelivery_of,omitempty"`
+	NextAttemptAt *time.Time          `json:"next_attempt_at,omitempty" bson:"next_attempt_at,omitempty"`
+	LastAttemptAt *time.Time          `json:"last_attempt_at,omitempty" bson:"last_attempt_at,omitempty"`
+	CreatedAt     *time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt     *time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
@@ -0,0 +1,349 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	maxLength = 500
+	maxDepth  = 16
+	maxTerms  = 32
+)
+
+type Error struct {
+	Pos     int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Pos+1)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+type Node interface{}
+
+type BinaryNode struct {
+	Op    string
+	Nodes []Node
+}
+
+type NotNode struct {
+	Node Node
+}
+
+type TermNode struct {
+	Field    string
+	Op       string
+	Value    string
+	Pos      int
+	ValuePos int
+	Bare     bool
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+	terms  int
+}
+
+func Parse(expr string) (Node, error) {
+	if len(expr) > maxLength {
+		return nil, &Error{Pos: maxLength, Message: fmt.Sprintf("expression must be at most %d characters long", maxLength)}
+	}
+
+	tokens, err := lex(expr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	if p.peek().kind == tokenEOF {
+		return nil, &Error{Pos: 0, Message: "expression is empty"}
+	}
+
+	node, err := p.parseOr()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &Error{Pos: t.pos, Message: fmt.Sprintf("unexpected '%s'", t.value)}
+	}
+
+	return node, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) keyword(t token, name string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.value, name)
+}
+
+func (p *parser) parseOr() (Node, error) {
+	node, err := p.parseAnd()
+
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []Node{node}
+
+	for p.keyword(p.peek(), "OR") {
+		p.next()
+		node, err := p.parseAnd()
+
+		if err != nil {
+			return nil, err
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+
+	return &BinaryNode{Op: "OR", Nodes: nodes}, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	node, err := p.parseNot()
+
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []Node{node}
+
+	for {
+		t := p.peek()
+
+		if p.keyword(t, "AND") {
+			p.next()
+		} else if t.kind == tokenEOF || t.kind == tokenRParen || p.keyword(t, "OR") {
+			break
+		}
+
+		node, err := p.parseNot()
+
+		if err != nil {
+			return nil, err
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+
+	return &BinaryNode{Op: "AND", Nodes: nodes}, nil
+}
+
+func (p *parser) parseNot() (Node, error) {
+	if p.keyword(p.peek(), "NOT") {
+		t := p.next()
+
+		if err := p.enter(t.pos); err != nil {
+			return nil, err
+		}
+
+		node, err := p.parseNot()
+		p.depth--
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &NotNode{Node: node}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenLParen:
+		if err := p.enter(t.pos); err != nil {
+			return nil, err
+		}
+
+		node, err := p.parseOr()
+		p.depth--
+
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, &Error{Pos: closing.pos, Message: "expected ')'"}
+		}
+
+		return node, nil
+	case tokenWord, tokenString:
+		if p.keyword(t, "AND") || p.keyword(t, "OR") || p.keyword(t, "NOT") {
+			return nil, &Error{Pos: t.pos, Message: fmt.Sprintf("expected a condition before '%s'", t.value)}
+		}
+
+		p.terms++
+
+		if p.terms > maxTerms {
+			return nil, &Error{Pos: t.pos, Message: fmt.Sprintf("expression can contain at most %d conditions", maxTerms)}
+		}
+
+		if t.kind == tokenString {
+			return &TermNode{Value: t.value, Pos: t.pos, ValuePos: t.pos, Bare: true}, nil
+		}
+
+		if p.peek().kind != tokenOp {
+			return &TermNode{Field: strings.ToLower(t.value), Value: t.value, Pos: t.pos, ValuePos: t.pos, Bare: true}, nil
+		}
+
+		op := p.next()
+		value := p.next()
+
+		if value.kind != tokenWord && value.kind != tokenString {
+			return nil, &Error{Pos: value.pos, Message: fmt.Sprintf("expected a value after '%s'", op.value)}
+		}
+
+		return &TermNode{Field: strings.ToLower(t.value), Op: op.value, Value: value.value, Pos: t.pos, ValuePos: value.pos}, nil
+	case tokenEOF:
+		return nil, &Error{Pos: t.pos, Message: "unexpected end of expression"}
+	case tokenOp:
+		if t.value == ":" {
+			return nil, &Error{Pos: t.pos, Message: "unexpected ':', values containing ':' such as RFC3339 timestamps must be quoted"}
+		}
+	}
+
+	return nil, &Error{Pos: t.pos, Message: fmt.Sprintf("unexpected '%s'", t.value)}
+}
+
+func (p *parser) enter(pos int) error {
+	p.depth++
+
+	if p.depth > maxDepth {
+		return &Error{Pos: pos, Message: fmt.Sprintf("expression can be nested at most %d levels deep", maxDepth)}
+	}
+
+	return nil
+}
+
+func lex(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+	offsets := make([]int, len(runes)+1)
+	offset := 0
+
+	for i, r := range runes {
+		offsets[i] = offset
+		offset += len(string(r))
+	}
+
+	offsets[len(runes)] = offset
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := offsets[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", pos: pos})
+			i++
+		case r == ':' || r == '=':
+			tokens = append(tokens, token{kind: tokenOp, value: string(r), pos: pos})
+			i++
+		case r == '<' || r == '>' || r == '!':
+			op := string(r)
+			i++
+
+			if i < len(runes) && runes[i] == '=' {
+				op += "="
+				i++
+			}
+
+			if op == "!" {
+				return nil, &Error{Pos: pos, Message: "expected '!='"}
+			}
+
+			tokens = append(tokens, token{kind: tokenOp, value: op, pos: pos})
+		case r == '"':
+			var b strings.Builder
+			i++
+			closed := false
+
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					b.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+
+				if runes[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+
+				b.WriteRune(runes[i])
+				i++
+			}
+
+			if !closed {
+				return nil, &Error{Pos: pos, Message: "unterminated quoted value"}
+			}
+
+			tokens = append(tokens, token{kind: tokenString, value: b.String(), pos: pos})
+		default:
+			start := i
+
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("():=<>!\"", runes[i]) {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: tokenWord, value: string(runes[start:i]), pos: pos})
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(expr)}), nil
+}
@@ -0,0 +1,148 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want Node
+	}{
+		{
+			name: "term",
+			expr: "priority:high",
+			want: &TermNode{Field: "priority", Op: ":", Value: "high", Pos: 0, ValuePos: 9},
+		},
+		{
+			name: "field is lowercased",
+			expr: "Priority>=medium",
+			want: &TermNode{Field: "priority", Op: ">=", Value: "medium", Pos: 0, ValuePos: 10},
+		},
+		{
+			name: "bare word",
+			expr: "done",
+			want: &TermNode{Field: "done", Value: "done", Pos: 0, ValuePos: 0, Bare: true},
+		},
+		{
+			name: "bare quoted string",
+			expr: `"weekly report"`,
+			want: &TermNode{Value: "weekly report", Pos: 0, ValuePos: 0, Bare: true},
+		},
+		{
+			name: "quoted value with escapes",
+			expr: `title:"say \"hi\""`,
+			want: &TermNode{Field: "title", Op: ":", Value: `say "hi"`, Pos: 0, ValuePos: 6},
+		},
+		{
+			name: "quoted timestamp",
+			expr: `due<"2026-11-01T09:00:00Z"`,
+			want: &TermNode{Field: "due", Op: "<", Value: "2026-11-01T09:00:00Z", Pos: 0, ValuePos: 4},
+		},
+		{
+			name: "implicit and",
+			expr: "done remind",
+			want: &BinaryNode{Op: "AND", Nodes: []Node{
+				&TermNode{Field: "done", Value: "done", Pos: 0, ValuePos: 0, Bare: true},
+				&TermNode{Field: "remind", Value: "remind", Pos: 5, ValuePos: 5, Bare: true},
+			}},
+		},
+		{
+			name: "and binds tighter than or",
+			expr: "a OR b and c",
+			want: &BinaryNode{Op: "OR", Nodes: []Node{
+				&TermNode{Field: "a", Value: "a", Pos: 0, ValuePos: 0, Bare: true},
+				&BinaryNode{Op: "AND", Nodes: []Node{
+					&TermNode{Field: "b", Value: "b", Pos: 5, ValuePos: 5, Bare: true},
+					&TermNode{Field: "c", Value: "c", Pos: 11, ValuePos: 11, Bare: true},
+				}},
+			}},
+		},
+		{
+			name: "double not",
+			expr: "NOT not done",
+			want: &NotNode{Node: &NotNode{Node: &TermNode{Field: "done", Value: "done", Pos: 8, ValuePos: 8, Bare: true}}},
+		},
+		{
+			name: "request example",
+			expr: "priority:high AND (label:work OR label:urgent) AND due<2026-11-01 AND NOT done",
+			want: &BinaryNode{Op: "AND", Nodes: []Node{
+				&TermNode{Field: "priority", Op: ":", Value: "high", Pos: 0, ValuePos: 9},
+				&BinaryNode{Op: "OR", Nodes: []Node{
+					&TermNode{Field: "label", Op: ":", Value: "work", Pos: 19, ValuePos: 25},
+					&TermNode{Field: "label", Op: ":", Value: "urgent", Pos: 33, ValuePos: 39},
+				}},
+				&TermNode{Field: "due", Op: "<", Value: "2026-11-01", Pos: 51, ValuePos: 55},
+				&NotNode{Node: &TermNode{Field: "done", Value: "done", Pos: 74, ValuePos: 74, Bare: true}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.expr)
+
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.expr, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %#v, want %#v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		pos     int
+		message string
+	}{
+		{"empty", "", 0, "expression is empty"},
+		{"blank", "   ", 0, "expression is empty"},
+		{"too long", strings.Repeat("a", maxLength+1), maxLength, "expression must be at most 500 characters long"},
+		{"missing value", "priority:", 9, "expected a value after ':'"},
+		{"operator as value", "priority:<", 9, "expected a value after ':'"},
+		{"missing closing paren", "(done", 5, "expected ')'"},
+		{"extra closing paren", "done)", 4, "unexpected ')'"},
+		{"empty parens", "()", 1, "unexpected ')'"},
+		{"leading keyword", "AND done", 0, "expected a condition before 'AND'"},
+		{"trailing keyword", "done OR", 7, "unexpected end of expression"},
+		{"dangling not", "done AND NOT", 12, "unexpected end of expression"},
+		{"bang without equals", "done ! x", 5, "expected '!='"},
+		{"unterminated string", `title:"abc`, 6, "unterminated quoted value"},
+		{"unquoted timestamp", "due<2026-11-01T09:00:00Z", 17, "unexpected ':', values containing ':' such as RFC3339 timestamps must be quoted"},
+		{"position counts bytes", "é done)", 7, "unexpected ')'"},
+		{"too deep", strings.Repeat("(", maxDepth+1) + "done" + strings.Repeat(")", maxDepth+1), maxDepth, "expression can be nested at most 16 levels deep"},
+		{"too many terms", strings.Repeat("done ", maxTerms+1), maxTerms * 5, "expression can contain at most 32 conditions"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			var qe *Error
+
+			if !errors.As(err, &qe) {
+				t.Fatalf("Parse(%q) error = %v, want *Error", tt.expr, err)
+			}
+
+			if qe.Pos != tt.pos || qe.Message != tt.message {
+				t.Errorf("Parse(%q) error = {%d %q}, want {%d %q}", tt.expr, qe.Pos, qe.Message, tt.pos, tt.message)
+			}
+		})
+	}
+}
+
+func TestErrorReportsOneBasedPosition(t *testing.T) {
+	err := &Error{Pos: 4, Message: "unexpected ')'"}
+
+	if got, want := err.Error(), "unexpected ')' at position 5"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,275 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type fieldKind int
+
+const (
+	kindLevel fieldKind = iota
+	kindLabel
+	kindBool
+	kindDate
+	kindText
+)
+
+type field struct {
+	kind  fieldKind
+	names []string
+}
+
+var fields = map[string]field{
+	"priority":    {kindLevel, []string{"priority"}},
+	"complexity":  {kindLevel, []string{"complexity"}},
+	"label":       {kindLabel, []string{"labels"}},
+	"labels":      {kindLabel, []string{"labels"}},
+	"done":        {kindBool, []string{"done"}},
+	"remind":      {kindBool, []string{"remind"}},
+	"due":         {kindDate, []string{"date"}},
+	"date":        {kindDate, []string{"date"}},
+	"created":     {kindDate, []string{"created_at"}},
+	"updated":     {kindDate, []string{"updated_at"}},
+	"completed":   {kindDate, []string{"done_at"}},
+	"title":       {kindText, []string{"title"}},
+	"description": {kindText, []string{"description"}},
+	"text":        {kindText, []string{"title", "description"}},
+}
+
+var levels = []string{"low", "medium", "high"}
+
+func Translate(node Node, now time.Time) (bson.D, error) {
+	switch n := node.(type) {
+	case *BinaryNode:
+		clauses := bson.A{}
+
+		for _, child := range n.Nodes {
+			clause, err := Translate(child, now)
+
+			if err != nil {
+				return nil, err
+			}
+
+			clauses = append(clauses, clause)
+		}
+
+		return bson.D{{Key: "$" + strings.ToLower(n.Op), Value: clauses}}, nil
+	case *NotNode:
+		clause, err := Translate(n.Node, now)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return bson.D{{Key: "$nor", Value: bson.A{clause}}}, nil
+	case *TermNode:
+		return translateTerm(n, now)
+	}
+
+	return nil, fmt.Errorf("unknown node %T", node)
+}
+
+func translateTerm(term *TermNode, now time.Time) (bson.D, error) {
+	if term.Bare {
+		if f, ok := fields[term.Field]; ok && f.kind == kindBool {
+			return bson.D{{Key: f.names[0], Value: true}}, nil
+		}
+
+		return textFilter(fields["text"].names, "", term.Value), nil
+	}
+
+	f, ok := fields[term.Field]
+
+	if !ok {
+		return nil, &Error{Pos: term.Pos, Message: fmt.Sprintf("unknown field '%s'", term.Field)}
+	}
+
+	name := f.names[0]
+	value := strings.TrimSpace(term.Value)
+
+	switch f.kind {
+	case kindLevel:
+		index := indexOf(levels, strings.ToLower(value))
+
+		if index < 0 {
+			return nil, &Error{Pos: term.ValuePos, Message: fmt.Sprintf("'%s' must be one of the following values: %s", term.Field, strings.Join(levels, " "))}
+		}
+
+		var selected []string
+
+		for i, level := range levels {
+			if compare(term.Op, i, index) {
+				selected = append(selected, level)
+			}
+		}
+
+		return bson.D{{Key: name, Value: bson.D{{Key: "$in", Value: selected}}}}, nil
+	case kindLabel:
+		pattern := primitive.Regex{Pattern: "^\\s*" + regexp.QuoteMeta(value) + "\\s*$", Options: "i"}
+
+		switch term.Op {
+		case ":", "=":
+			return bson.D{{Key: name, Value: pattern}}, nil
+		case "!=":
+			return bson.D{{Key: name, Value: bson.D{{Key: "$not", Value: pattern}}}}, nil
+		}
+	case kindBool:
+		var b bool
+
+		switch strings.ToLower(value) {
+		case "true", "yes":
+			b = true
+		case "false", "no":
+			b = false
+		default:
+			return nil, &Error{Pos: term.ValuePos, Message: fmt.Sprintf("'%s' must be true or false", term.Field)}
+		}
+
+		switch term.Op {
+		case ":", "=":
+			if !b {
+				return bson.D{{Key: name, Value: bson.D{{Key: "$ne", Value: true}}}}, nil
+			}
+
+			return bson.D{{Key: name, Value: true}}, nil
+		case "!=":
+			if b {
+				return bson.D{{Key: name, Value: bson.D{{Key: "$ne", Value: true}}}}, nil
+			}
+
+			return bson.D{{Key: name, Value: true}}, nil
+		}
+	case kindDate:
+		t, day, err := parseDate(value, now)
+
+		if err != nil {
+			return nil, &Error{Pos: term.ValuePos, Message: fmt.Sprintf("'%s' must be a date like 2026-11-01, today, tomorrow or yesterday, or a quoted RFC3339 timestamp like \"2026-11-01T09:00:00Z\"", term.Field)}
+		}
+
+		return dateFilter(name, term.Op, t, day), nil
+	case kindText:
+		switch term.Op {
+		case ":", "=":
+			return textFilter(f.names, "", value), nil
+		case "!=":
+			return textFilter(f.names, "$not", value), nil
+		}
+	}
+
+	return nil, &Error{Pos: term.Pos, Message: fmt.Sprintf("operator '%s' is not supported for '%s'", term.Op, term.Field)}
+}
+
+func textFilter(names []string, op string, value string) bson.D {
+	pattern := primitive.Regex{Pattern: regexp.QuoteMeta(value), Options: "i"}
+	clauses := bson.A{}
+
+	for _, name := range names {
+		if op == "" {
+			clauses = append(clauses, bson.D{{Key: name, Value: pattern}})
+		} else {
+			clauses = append(clauses, bson.D{{Key: name, Value: bson.D{{Key: op, Value: pattern}}}})
+		}
+	}
+
+	if len(clauses) == 1 {
+		return clauses[0].(bson.D)
+	}
+
+	if op == "" {
+		return bson.D{{Key: "$or", Value: clauses}}
+	}
+
+	return bson.D{{Key: "$and", Value: clauses}}
+}
+
+func dateFilter(name string, op string, t time.Time, day bool) bson.D {
+	end := t.AddDate(0, 0, 1)
+	var cond bson.D
+
+	switch op {
+	case ":", "=":
+		if !day {
+			return bson.D{{Key: name, Value: t}}
+		}
+
+		cond = bson.D{{Key: "$gte", Value: t}, {Key: "$lt", Value: end}}
+	case "!=":
+		if !day {
+			cond = bson.D{{Key: "$ne", Value: t}}
+		} else {
+			cond = bson.D{{Key: "$not", Value: bson.D{{Key: "$gte", Value: t}, {Key: "$lt", Value: end}}}}
+		}
+	case "<":
+		cond = bson.D{{Key: "$lt", Value: t}}
+	case "<=":
+		if day {
+			cond = bson.D{{Key: "$lt", Value: end}}
+		} else {
+			cond = bson.D{{Key: "$lte", Value: t}}
+		}
+	case ">":
+		if day {
+			cond = bson.D{{Key: "$gte", Value: end}}
+		} else {
+			cond = bson.D{{Key: "$gt", Value: t}}
+		}
+	case ">=":
+		cond = bson.D{{Key: "$gte", Value: t}}
+	}
+
+	return bson.D{{Key: name, Value: cond}}
+}
+
+func parseDate(value string, now time.Time) (time.Time, bool, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch strings.ToLower(value) {
+	case "today":
+		return today, true, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true, nil
+	}
+
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+
+	return t, false, err
+}
+
+func compare(op string, a int, b int) bool {
+	switch op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "!=":
+		return a != b
+	}
+
+	return a == b
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
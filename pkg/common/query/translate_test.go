@@ -0,0 +1,198 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTranslate(t *testing.T) {
+	now := time.Date(2026, 10, 19, 15, 30, 0, 0, time.UTC)
+	today := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	nov1 := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	nov2 := time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)
+	instant := time.Date(2026, 11, 1, 9, 0, 0, 0, time.UTC)
+
+	label := func(value string) primitive.Regex {
+		return primitive.Regex{Pattern: `^\s*` + value + `\s*$`, Options: "i"}
+	}
+
+	text := func(value string) primitive.Regex {
+		return primitive.Regex{Pattern: value, Options: "i"}
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		want bson.D
+	}{
+		{
+			name: "request example",
+			expr: "priority:high AND (label:work OR label:urgent) AND due<2026-11-01 AND NOT done",
+			want: bson.D{{Key: "$and", Value: bson.A{
+				bson.D{{Key: "priority", Value: bson.D{{Key: "$in", Value: []string{"high"}}}}},
+				bson.D{{Key: "$or", Value: bson.A{
+					bson.D{{Key: "labels", Value: label("work")}},
+					bson.D{{Key: "labels", Value: label("urgent")}},
+				}}},
+				bson.D{{Key: "date", Value: bson.D{{Key: "$lt", Value: nov1}}}},
+				bson.D{{Key: "$nor", Value: bson.A{bson.D{{Key: "done", Value: true}}}}},
+			}}},
+		},
+		{
+			name: "level comparison",
+			expr: "priority>=medium",
+			want: bson.D{{Key: "priority", Value: bson.D{{Key: "$in", Value: []string{"medium", "high"}}}}},
+		},
+		{
+			name: "level is case insensitive",
+			expr: "complexity!=LOW",
+			want: bson.D{{Key: "complexity", Value: bson.D{{Key: "$in", Value: []string{"medium", "high"}}}}},
+		},
+		{
+			name: "label metacharacters are escaped",
+			expr: `label:"a.*(b)"`,
+			want: bson.D{{Key: "labels", Value: label(`a\.\*\(b\)`)}},
+		},
+		{
+			name: "label not equal",
+			expr: "label!=work",
+			want: bson.D{{Key: "labels", Value: bson.D{{Key: "$not", Value: label("work")}}}},
+		},
+		{
+			name: "bool true",
+			expr: "remind:yes",
+			want: bson.D{{Key: "remind", Value: true}},
+		},
+		{
+			name: "bool false matches missing fields",
+			expr: "done:false",
+			want: bson.D{{Key: "done", Value: bson.D{{Key: "$ne", Value: true}}}},
+		},
+		{
+			name: "bool not equal false",
+			expr: "done!=false",
+			want: bson.D{{Key: "done", Value: true}},
+		},
+		{
+			name: "day equality",
+			expr: "due:today",
+			want: bson.D{{Key: "date", Value: bson.D{{Key: "$gte", Value: today}, {Key: "$lt", Value: today.AddDate(0, 0, 1)}}}},
+		},
+		{
+			name: "day not equal",
+			expr: "due!=2026-11-01",
+			want: bson.D{{Key: "date", Value: bson.D{{Key: "$not", Value: bson.D{{Key: "$gte", Value: nov1}, {Key: "$lt", Value: nov2}}}}}},
+		},
+		{
+			name: "day less or equal includes the whole day",
+			expr: "due<=2026-11-01",
+			want: bson.D{{Key: "date", Value: bson.D{{Key: "$lt", Value: nov2}}}},
+		},
+		{
+			name: "day greater excludes the whole day",
+			expr: "completed>2026-11-01",
+			want: bson.D{{Key: "done_at", Value: bson.D{{Key: "$gte", Value: nov2}}}},
+		},
+		{
+			name: "relative day",
+			expr: "created>=yesterday",
+			want: bson.D{{Key: "created_at", Value: bson.D{{Key: "$gte", Value: today.AddDate(0, 0, -1)}}}},
+		},
+		{
+			name: "quoted timestamp",
+			expr: `updated<="2026-11-01T09:00:00Z"`,
+			want: bson.D{{Key: "updated_at", Value: bson.D{{Key: "$lte", Value: instant}}}},
+		},
+		{
+			name: "timestamp equality",
+			expr: `due="2026-11-01T09:00:00Z"`,
+			want: bson.D{{Key: "date", Value: instant}},
+		},
+		{
+			name: "text field",
+			expr: "title:report",
+			want: bson.D{{Key: "title", Value: text("report")}},
+		},
+		{
+			name: "text across fields",
+			expr: `text!="a+b"`,
+			want: bson.D{{Key: "$and", Value: bson.A{
+				bson.D{{Key: "title", Value: bson.D{{Key: "$not", Value: text(`a\+b`)}}}},
+				bson.D{{Key: "description", Value: bson.D{{Key: "$not", Value: text(`a\+b`)}}}},
+			}}},
+		},
+		{
+			name: "bare word searches text",
+			expr: "report",
+			want: bson.D{{Key: "$or", Value: bson.A{
+				bson.D{{Key: "title", Value: text("report")}},
+				bson.D{{Key: "description", Value: text("report")}},
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := Parse(tt.expr)
+
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.expr, err)
+			}
+
+			got, err := Translate(node, now)
+
+			if err != nil {
+				t.Fatalf("Translate(%q) returned error: %v", tt.expr, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Translate(%q) = %#v, want %#v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		pos     int
+		message string
+	}{
+		{"unknown field", "done AND owner:me", 9, "unknown field 'owner'"},
+		{"invalid level", "priority:urgent", 9, "'priority' must be one of the following values: low medium high"},
+		{"invalid bool", "done:maybe", 5, "'done' must be true or false"},
+		{"invalid date", "due<soon", 4, `'due' must be a date like 2026-11-01, today, tomorrow or yesterday, or a quoted RFC3339 timestamp like "2026-11-01T09:00:00Z"`},
+		{"invalid timestamp", `due<"2026-11-01 09:00"`, 4, `'due' must be a date like 2026-11-01, today, tomorrow or yesterday, or a quoted RFC3339 timestamp like "2026-11-01T09:00:00Z"`},
+		{"unsupported label operator", "label<work", 0, "operator '<' is not supported for 'label'"},
+		{"unsupported bool operator", "done>=true", 0, "operator '>=' is not supported for 'done'"},
+		{"unsupported text operator", "title>a", 0, "operator '>' is not supported for 'title'"},
+		{"nested error", "(done OR NOT priority:none)", 22, "'priority' must be one of the following values: low medium high"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := Parse(tt.expr)
+
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.expr, err)
+			}
+
+			_, err = Translate(node, time.Now())
+			var qe *Error
+
+			if !errors.As(err, &qe) {
+				t.Fatalf("Translate(%q) error = %v, want *Error", tt.expr, err)
+			}
+
+			if qe.Pos != tt.pos || qe.Message != tt.message {
+				t.Errorf("Translate(%q) error = {%d %q}, want {%d %q}", tt.expr, qe.Pos, qe.Message, tt.pos, tt.message)
+			}
+		})
+	}
+}
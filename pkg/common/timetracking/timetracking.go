@@ -0,0 +1,202 @@
+package timetracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const Collection = "time_entries"
+
+type total struct {
+	TaskId    primitive.ObjectID `bson:"_id"`
+	Seconds   int64              `bson:"seconds"`
+	RunningAt *time.Time         `bson:"running_at"`
+}
+
+func Elapsed(entry models.TimeEntry, now time.Time) int64 {
+	if entry.Running != nil && *entry.Running && entry.StartedAt != nil {
+		return int64(now.Sub(*entry.StartedAt).Seconds())
+	}
+
+	if entry.Seconds != nil {
+		return *entry.Seconds
+	}
+
+	return 0
+}
+
+func Totals(ctx context.Context, db *mongo.Database, uid primitive.ObjectID, taskIds []primitive.ObjectID) (map[primitive.ObjectID]int64, error) {
+	totals := make(map[primitive.ObjectID]int64, len(taskIds))
+
+	if len(taskIds) == 0 {
+		return totals, nil
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "user_id", Value: uid},
+			{Key: "task_id", Value: bson.D{{Key: "$in", Value: taskIds}}},
+		}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$task_id"},
+			{Key: "seconds", Value: bson.D{{Key: "$sum", Value: "$seconds"}}},
+			{Key: "running_at", Value: bson.D{{Key: "$max", Value: bson.D{{Key: "$cond", Value: bson.A{
+				bson.D{{Key: "$eq", Value: bson.A{"$running", true}}}, "$started_at", nil,
+			}}}}}},
+		}}},
+	}
+
+	cursor, err := db.Collection(Collection).Aggregate(ctx, pipeline)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []total
+
+	if err = cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	for _, row := range rows {
+		seconds := row.Seconds
+
+		if row.RunningAt != nil {
+			seconds += int64(now.Sub(*row.RunningAt).Seconds())
+		}
+
+		totals[row.TaskId] = seconds
+	}
+
+	return totals, nil
+}
+
+func Attach(ctx context.Context, db *mongo.Database, uid primitive.ObjectID, tasks []models.Task) error {
+	ids := make([]primitive.ObjectID, 0, len(tasks))
+
+	for _, task := range tasks {
+		if task.Id != nil {
+			ids = append(ids, *task.Id)
+		}
+	}
+
+	totals, err := Totals(ctx, db, uid, ids)
+
+	if err != nil {
+		return err
+	}
+
+	for i := range tasks {
+		if tasks[i].Id == nil {
+			continue
+		}
+
+		seconds := totals[*tasks[i].Id]
+		tasks[i].Tracked = &seconds
+	}
+
+	return nil
+}
+
+func Running(ctx context.Context, db *mongo.Database, uid primitive.ObjectID) (*models.TimeEntry, error) {
+	filter := bson.D{
+		{Key: "user_id", Value: uid},
+		{Key: "running", Value: true},
+	}
+
+	var entry models.TimeEntry
+
+	if err := db.Collection(Collection).FindOne(ctx, filter).Decode(&entry); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func Start(ctx context.Context, db *mongo.Database, uid primitive.ObjectID, taskId primitive.ObjectID, note *string) (*models.TimeEntry, error) {
+	now := time.Now()
+	running := true
+	source := models.TimeEntrySourceTimer
+
+	entry := models.TimeEntry{
+		UserId:    &uid,
+		TaskId:    &taskId,
+		StartedAt: &now,
+		Running:   &running,
+		Note:      note,
+		Source:    &source,
+		CreatedAt: &now,
+		UpdatedAt: &now,
+	}
+
+	result, err := db.Collection(Collection).InsertOne(ctx, entry)
+
+	if err != nil {
+		return nil, err
+	}
+
+	id := result.InsertedID.(primitive.ObjectID)
+	entry.Id = &id
+
+	return &entry, nil
+}
+
+func Stop(ctx context.Context, db *mongo.Database, uid primitive.ObjectID, taskId *primitive.ObjectID) (*models.TimeEntry, error) {
+	now := time.Now()
+
+	filter := bson.D{
+		{Key: "user_id", Value: uid},
+		{Key: "running", Value: true},
+	}
+
+	if taskId != nil {
+		filter = append(filter, bson.E{Key: "task_id", Value: taskId})
+	}
+
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "ended_at", Value: now},
+			{Key: "seconds", Value: bson.D{{Key: "$toLong", Value: bson.D{{Key: "$floor", Value: bson.D{{Key: "$divide", Value: bson.A{
+				bson.D{{Key: "$subtract", Value: bson.A{now, "$started_at"}}}, 1000,
+			}}}}}}}},
+			{Key: "updated_at", Value: now},
+		}}},
+		{{Key: "$unset", Value: "running"}},
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var entry models.TimeEntry
+
+	if err := db.Collection(Collection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&entry); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func DeleteForTasks(ctx context.Context, db *mongo.Database, taskIds []primitive.ObjectID) error {
+	if len(taskIds) == 0 {
+		return nil
+	}
+
+	filter := bson.D{{Key: "task_id", Value: bson.D{{Key: "$in", Value: taskIds}}}}
+	_, err := db.Collection(Collection).DeleteMany(ctx, filter)
+
+	return err
+}
@@ -0,0 +1,56 @@
+package timezone
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrInvalid = errors.New("invalid time zone")
+
+func Load(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, ErrInvalid
+	}
+
+	loc, err := time.LoadLocation(name)
+
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	return loc, nil
+}
+
+func ForUser(ctx context.Context, db *mongo.Database, uid *primitive.ObjectID, override string) (*time.Location, error) {
+	if override != "" {
+		return Load(override)
+	}
+
+	var settings models.Settings
+	opts := options.FindOne().SetProjection(bson.D{{Key: "timezone", Value: 1}})
+
+	if err := db.Collection("settings").FindOne(ctx, bson.D{{Key: "user_id", Value: uid}}, opts).Decode(&settings); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return time.UTC, nil
+		}
+
+		return nil, err
+	}
+
+	if settings.Timezone == nil {
+		return time.UTC, nil
+	}
+
+	if loc, err := Load(*settings.Timezone); err == nil {
+		return loc, nil
+	}
+
+	return time.UTC, nil
+}
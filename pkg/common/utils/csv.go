@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+)
+
+var taskCSVHeader = []string{
+	"id",
+	"title",
+	"description",
+	"labels",
+	"priority",
+	"complexity",
+	"date",
+	"from",
+	"to",
+	"done",
+	"remind",
+	"status",
+	"created_at",
+	"updated_at",
+}
+
+func WriteTasksCSV(w io.Writer, tasks []models.Task) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(taskCSVHeader); err != nil {
+		return err
+	}
+
+	for _, t := range tasks {
+		var labels string
+
+		if t.Labels != nil {
+			labels = strings.Join(*t.Labels, ";")
+		}
+
+		var id string
+
+		if t.Id != nil {
+			id = t.Id.Hex()
+		}
+
+		record := []string{
+			id,
+			csvString(t.Title),
+			csvString(t.Description),
+			labels,
+			csvString(t.Priority),
+			csvString(t.Complexity),
+			csvTime(t.Date),
+			csvTime(t.From),
+			csvTime(t.To),
+			csvBool(t.Done),
+			csvBool(t.Remind),
+			csvString(t.Status),
+			csvTime(t.CreatedAt),
+			csvTime(t.UpdatedAt),
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func csvString(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+func csvBool(b *bool) string {
+	if b == nil {
+		return ""
+	}
+
+	return strconv.FormatBool(*b)
+}
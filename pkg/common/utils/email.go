@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"crypto/tls"
+	"os"
+
+	gomail "gopkg.in/mail.v2"
+)
+
+func SendEmail(to string, subject string, body string) error {
+	m := gomail.NewMessage()
+	from := os.Getenv("SENDER_EMAIL")
+	password := os.Getenv("SENDER_PASSWORD")
+	host := "smtp.gmail.com"
+	port := 587
+
+	m.SetHeader("From", from)
+	m.SetHeader("To", to)
+	m.SetHeader("Subject", subject)
+	m.SetBody("text/html", body)
+	d := gomail.NewDialer(host, port, from, password)
+	d.TLSConfig = &tls.Config{InsecureSkipVerify: true}
+
+	return d.DialAndSend(m)
+}
@@ -2,23 +2,50 @@ package utils
 
 import (
 	"fmt"
+	"reflect"
 
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
 	"github.com/go-playground/validator/v10"
 )
 
-type ErrorMsg struct {
-	Field   string `json:"field"`
-	Message string `json:"message"`
-}
+type ErrorMsg = models.ErrorMsg
 
 func GetErrorMsg(fe validator.FieldError) string {
 	switch fe.Tag() {
 	case "required":
 		return "this field is required"
+	case "required_without":
+		return fmt.Sprintf("this field is required when %v is not present", fe.Param())
 	case "email":
 		return "invalid email"
 	case "boolean":
 		return "this field must be of type boolean"
+	case "min":
+		if fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("this field must contain at least %v element(s)", fe.Param())
+		}
+
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("this field must be at least %v characters long", fe.Param())
+		}
+
+		return fmt.Sprintf("this field must be greater than or equal to %v", fe.Param())
+	case "max":
+		if fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("this field must contain at most %v element(s)", fe.Param())
+		}
+
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("this field must be at most %v characters long", fe.Param())
+		}
+
+		return fmt.Sprintf("this field must be less than or equal to %v", fe.Param())
+	case "hexcolor":
+		return "this field must be a valid hex color"
+	case "url":
+		return "this field must be a valid URL"
+	case "timezone":
+		return "this field must be a valid IANA time zone"
 	case "oneof":
 		return fmt.Sprintf("this field must be one of the following values: %v", fe.Param())
 	}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var ErrInvalidETag = errors.New("invalid ETag in If-Match header")
+
+func ETag(version *int64) string {
+	var v int64
+
+	if version != nil {
+		v = *version
+	}
+
+	return fmt.Sprintf("\"%d\"", v)
+}
+
+func SetETag(c *gin.Context, version *int64) {
+	c.Header("ETag", ETag(version))
+}
+
+func IfMatch(c *gin.Context) (*int64, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	version, err := parseETag(header)
+
+	if err != nil {
+		return nil, ErrInvalidETag
+	}
+
+	return &version, nil
+}
+
+func IfNoneMatch(c *gin.Context, version *int64) bool {
+	return MatchesETag(c, ETag(version))
+}
+
+func MatchesETag(c *gin.Context, current string) bool {
+	header := strings.TrimSpace(c.GetHeader("If-None-Match"))
+
+	if header == "" {
+		return false
+	}
+
+	if header == "*" {
+		return true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == current {
+			return true
+		}
+	}
+
+	return false
+}
+
+func VersionFilter(version int64) bson.E {
+	if version == 0 {
+		return bson.E{Key: "version", Value: bson.D{{Key: "$in", Value: bson.A{nil, 0}}}}
+	}
+
+	return bson.E{Key: "version", Value: version}
+}
+
+func VersionOf(doc bson.M) int64 {
+	switch v := doc["version"].(type) {
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	default:
+		return 0
+	}
+}
+
+func parseETag(tag string) (int64, error) {
+	tag = strings.TrimPrefix(tag, "W/")
+
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, ErrInvalidETag
+	}
+
+	return strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+}
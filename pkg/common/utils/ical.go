@@ -0,0 +1,389 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+)
+
+const (
+	icalDateTime = "20060102T150405Z"
+	icalDate     = "20060102"
+)
+
+var icalPriorities = map[string]int{
+	"high":   1,
+	"medium": 5,
+	"low":    9,
+}
+
+type icalWriter struct {
+	w   io.Writer
+	err error
+}
+
+func WriteTasksICS(w io.Writer, name string, tasks []models.Task) error {
+	iw := &icalWriter{w: w}
+	now := time.Now()
+
+	iw.line("BEGIN:VCALENDAR")
+	iw.line("VERSION:2.0")
+	iw.line("PRODID:-//Tasker//Tasks//EN")
+	iw.line("CALSCALE:GREGORIAN")
+	iw.line("METHOD:PUBLISH")
+	iw.property("X-WR-CALNAME", icalText(name))
+
+	for _, t := range tasks {
+		if t.Id == nil {
+			continue
+		}
+
+		component := "VTODO"
+
+		if t.From != nil && t.To != nil {
+			component = "VEVENT"
+		}
+
+		iw.line("BEGIN:" + component)
+		iw.property("UID", t.Id.Hex()+"@tasker")
+		iw.property("DTSTAMP", icalTime(t.UpdatedAt, now))
+
+		if t.CreatedAt != nil {
+			iw.property("CREATED", t.CreatedAt.UTC().Format(icalDateTime))
+		}
+
+		if t.UpdatedAt != nil {
+			iw.property("LAST-MODIFIED", t.UpdatedAt.UTC().Format(icalDateTime))
+		}
+
+		if t.Version != nil {
+			iw.property("SEQUENCE", strconv.FormatInt(*t.Version, 10))
+		}
+
+		if t.Title != nil {
+			iw.property("SUMMARY", icalText(*t.Title))
+		}
+
+		if t.Description != nil && *t.Description != "" {
+			iw.property("DESCRIPTION", icalText(*t.Description))
+		}
+
+		if t.Labels != nil && len(*t.Labels) > 0 {
+			labels := make([]string, len(*t.Labels))
+
+			for i, label := range *t.Labels {
+				labels[i] = icalText(label)
+			}
+
+			iw.property("CATEGORIES", strings.Join(labels, ","))
+		}
+
+		if t.Priority != nil {
+			if priority, ok := icalPriorities[*t.Priority]; ok {
+				iw.property("PRIORITY", strconv.Itoa(priority))
+			}
+		}
+
+		if component == "VEVENT" {
+			iw.property("DTSTART", t.From.UTC().Format(icalDateTime))
+			iw.property("DTEND", t.To.UTC().Format(icalDateTime))
+			iw.property("TRANSP", "OPAQUE")
+		} else {
+			if t.Date != nil {
+				iw.property("DUE;VALUE=DATE", t.Date.UTC().Format(icalDate))
+			}
+
+			if t.Done != nil && *t.Done {
+				iw.property("STATUS", "COMPLETED")
+				iw.property("PERCENT-COMPLETE", "100")
+
+				if t.DoneAt != nil {
+					iw.property("COMPLETED", t.DoneAt.UTC().Format(icalDateTime))
+				}
+			} else {
+				iw.property("STATUS", "NEEDS-ACTION")
+			}
+		}
+
+		if t.Remind != nil && *t.Remind && (component == "VEVENT" || t.Date != nil) {
+			iw.line("BEGIN:VALARM")
+			iw.property("ACTION", "DISPLAY")
+			iw.property("TRIGGER", fmt.Sprintf("-PT%dM", icalAlarmMinutes()))
+
+			if t.Title != nil {
+				iw.property("DESCRIPTION", icalText(*t.Title))
+			} else {
+				iw.property("DESCRIPTION", "Reminder")
+			}
+
+			iw.line("END:VALARM")
+		}
+
+		iw.line("END:" + component)
+	}
+
+	iw.line("END:VCALENDAR")
+
+	return iw.err
+}
+
+func (iw *icalWriter) property(name string, value string) {
+	iw.line(name + ":" + value)
+}
+
+func (iw *icalWriter) line(content string) {
+	if iw.err != nil {
+		return
+	}
+
+	limit := 75
+
+	for len(content) > limit {
+		cut := limit
+
+		for cut > 0 && !isUTF8Start(content[cut]) {
+			cut--
+		}
+
+		if _, iw.err = io.WriteString(iw.w, content[:cut]+"\r\n "); iw.err != nil {
+			return
+		}
+
+		content = content[cut:]
+		limit = 74
+	}
+
+	_, iw.err = io.WriteString(iw.w, content+"\r\n")
+}
+
+func isUTF8Start(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+func icalText(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, ";", "\\;")
+	s = strings.ReplaceAll(s, ",", "\\,")
+	s = strings.ReplaceAll(s, "\r\n", "\\n")
+	s = strings.ReplaceAll(s, "\n", "\\n")
+
+	return strings.ReplaceAll(s, "\r", "\\n")
+}
+
+func icalTime(t *time.Time, fallback time.Time) string {
+	if t == nil {
+		return fallback.UTC().Format(icalDateTime)
+	}
+
+	return t.UTC().Format(icalDateTime)
+}
+
+func icalAlarmMinutes() int {
+	minutes, err := strconv.Atoi(os.Getenv("ICAL_ALARM_MINUTES"))
+
+	if err != nil || minutes < 0 {
+		minutes = 15
+	}
+
+	return minutes
+}
+
+type ICSProperty struct {
+	Params map[string]string
+	Value  string
+}
+
+type ICSComponent struct {
+	Name       string
+	Properties map[string][]ICSProperty
+	Components []*ICSComponent
+}
+
+func (component *ICSComponent) Get(name string) *ICSProperty {
+	if props := component.Properties[name]; len(props) > 0 {
+		return &props[0]
+	}
+
+	return nil
+}
+
+func (component *ICSComponent) Text(name string) string {
+	if prop := component.Get(name); prop != nil {
+		return icalUnescape(prop.Value)
+	}
+
+	return ""
+}
+
+func ParseICS(r io.Reader) ([]*ICSComponent, error) {
+	data, err := io.ReadAll(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\n ", "")
+	content = strings.ReplaceAll(content, "\n\t", "")
+
+	var roots []*ICSComponent
+	var stack []*ICSComponent
+
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		if line == "" {
+			continue
+		}
+
+		sep := icalValueSeparator(line)
+
+		if sep < 0 {
+			return nil, fmt.Errorf("invalid iCalendar line %d", i+1)
+		}
+
+		parts := strings.Split(line[:sep], ";")
+		name := strings.ToUpper(parts[0])
+		value := line[sep+1:]
+
+		switch name {
+		case "BEGIN":
+			component := &ICSComponent{Name: strings.ToUpper(value), Properties: map[string][]ICSProperty{}}
+
+			if len(stack) == 0 {
+				roots = append(roots, component)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, component)
+			}
+
+			stack = append(stack, component)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(value) {
+				return nil, fmt.Errorf("unexpected END:%s on line %d", value, i+1)
+			}
+
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("property outside of a component on line %d", i+1)
+			}
+
+			prop := ICSProperty{Params: map[string]string{}, Value: value}
+
+			for _, param := range parts[1:] {
+				if key, val, ok := strings.Cut(param, "="); ok {
+					prop.Params[strings.ToUpper(key)] = strings.Trim(val, "\"")
+				}
+			}
+
+			component := stack[len(stack)-1]
+			component.Properties[name] = append(component.Properties[name], prop)
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("missing END:%s", stack[len(stack)-1].Name)
+	}
+
+	return roots, nil
+}
+
+func ParseICSTime(prop *ICSProperty) (*time.Time, error) {
+	value := strings.TrimSpace(prop.Value)
+
+	if prop.Params["VALUE"] == "DATE" || len(value) == len(icalDate) {
+		t, err := time.Parse(icalDate, value)
+		return &t, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(icalDateTime, value)
+		return &t, err
+	}
+
+	location := time.UTC
+
+	if tzid := prop.Params["TZID"]; tzid != "" {
+		if loc, err := time.LoadLocation(tzid); err == nil {
+			location = loc
+		}
+	}
+
+	t, err := time.ParseInLocation(strings.TrimSuffix(icalDateTime, "Z"), value, location)
+	return &t, err
+}
+
+func ICSCategories(component *ICSComponent) []string {
+	var categories []string
+
+	for _, prop := range component.Properties["CATEGORIES"] {
+		for _, category := range icalSplit(prop.Value) {
+			if category = strings.TrimSpace(icalUnescape(category)); category != "" {
+				categories = append(categories, category)
+			}
+		}
+	}
+
+	return categories
+}
+
+func icalValueSeparator(line string) int {
+	quoted := false
+
+	for i, r := range line {
+		switch r {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func icalSplit(value string) []string {
+	var parts []string
+	start := 0
+
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' {
+			i++
+		} else if value[i] == ',' {
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, value[start:])
+}
+
+func icalUnescape(s string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,398 @@
+package utils
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestICalTextRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		escaped string
+		out     string
+	}{
+		{"plain", "Buy milk", "Buy milk", "Buy milk"},
+		{"separators", "a,b;c", `a\,b\;c`, "a,b;c"},
+		{"backslash", `C:\tasks\n`, `C:\\tasks\\n`, `C:\tasks\n`},
+		{"newline", "one\ntwo", `one\ntwo`, "one\ntwo"},
+		{"crlf", "one\r\ntwo", `one\ntwo`, "one\ntwo"},
+		{"carriage return", "one\rtwo", `one\ntwo`, "one\ntwo"},
+		{"utf-8", "café ☕, ñandú", `café ☕\, ñandú`, "café ☕, ñandú"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			escaped := icalText(tt.in)
+
+			if escaped != tt.escaped {
+				t.Errorf("icalText(%q) = %q, want %q", tt.in, escaped, tt.escaped)
+			}
+
+			if got := icalUnescape(escaped); got != tt.out {
+				t.Errorf("icalUnescape(%q) = %q, want %q", escaped, got, tt.out)
+			}
+		})
+	}
+}
+
+func TestICalLineFolding(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"short", "SUMMARY:short"},
+		{"exactly 75 octets", "SUMMARY:" + strings.Repeat("a", 67)},
+		{"ascii", "SUMMARY:" + strings.Repeat("abcdefghij", 20)},
+		{"two byte runes", "SUMMARY:" + strings.Repeat("ñ", 120)},
+		{"three byte runes", "SUMMARY:" + strings.Repeat("☕", 90)},
+		{"four byte runes", "SUMMARY:x" + strings.Repeat("😀", 60)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			iw := &icalWriter{w: &buf}
+			iw.line(tt.content)
+
+			if iw.err != nil {
+				t.Fatalf("line() returned error: %v", iw.err)
+			}
+
+			out := buf.String()
+
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("output %q doesn't end with CRLF", out)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+
+			for i, line := range lines {
+				if len(line) > 75 {
+					t.Errorf("line %d is %d octets long, want at most 75", i+1, len(line))
+				}
+
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d doesn't start with a space: %q", i+1, line)
+				}
+
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a UTF-8 sequence: %q", i+1, line)
+				}
+			}
+
+			if len(tt.content) <= 75 && len(lines) != 1 {
+				t.Errorf("content of %d octets was folded into %d lines", len(tt.content), len(lines))
+			}
+
+			if got := strings.ReplaceAll(out, "\r\n ", ""); got != tt.content+"\r\n" {
+				t.Errorf("unfolded output = %q, want %q", got, tt.content+"\r\n")
+			}
+		})
+	}
+}
+
+func TestWriteTasksICS(t *testing.T) {
+	t.Setenv("ICAL_ALARM_MINUTES", "30")
+
+	id := func() *primitive.ObjectID {
+		oid := primitive.NewObjectID()
+		return &oid
+	}
+
+	str := func(s string) *string { return &s }
+	flag := func(b bool) *bool { return &b }
+	at := func(t time.Time) *time.Time { return &t }
+	version := int64(3)
+
+	created := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	updated := time.Date(2026, 10, 2, 9, 30, 0, 0, time.UTC)
+	due := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	from := time.Date(2026, 11, 2, 14, 0, 0, 0, time.FixedZone("UTC-5", -5*60*60))
+	to := from.Add(time.Hour)
+	doneAt := time.Date(2026, 10, 3, 10, 0, 0, 0, time.UTC)
+	longTitle := strings.Repeat("Revisar el informe de café ☕ ", 6)
+
+	todo := models.Task{
+		Id:          id(),
+		Title:       str(longTitle),
+		Description: str("first line\nsecond; with, separators"),
+		Labels:      &[]string{"work", "a,b"},
+		Priority:    str("high"),
+		Date:        at(due),
+		Remind:      flag(true),
+		Version:     &version,
+		CreatedAt:   at(created),
+		UpdatedAt:   at(updated),
+	}
+
+	done := models.Task{
+		Id:     id(),
+		Title:  str("Done task"),
+		Done:   flag(true),
+		DoneAt: at(doneAt),
+		Remind: flag(true),
+	}
+
+	event := models.Task{
+		Id:       id(),
+		Title:    str("Meeting"),
+		Priority: str("low"),
+		From:     at(from),
+		To:       at(to),
+		Remind:   flag(true),
+	}
+
+	var buf bytes.Buffer
+
+	if err := WriteTasksICS(&buf, "My, tasks", []models.Task{todo, {Title: str("no id")}, done, event}); err != nil {
+		t.Fatalf("WriteTasksICS returned error: %v", err)
+	}
+
+	for i, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > 75 || !utf8.ValidString(line) {
+			t.Errorf("line %d is not folded correctly: %q", i+1, line)
+		}
+	}
+
+	roots, err := ParseICS(&buf)
+
+	if err != nil {
+		t.Fatalf("ParseICS returned error: %v", err)
+	}
+
+	if len(roots) != 1 || roots[0].Name != "VCALENDAR" {
+		t.Fatalf("expected a single VCALENDAR, got %#v", roots)
+	}
+
+	calendar := roots[0]
+
+	if got := calendar.Text("X-WR-CALNAME"); got != "My, tasks" {
+		t.Errorf("X-WR-CALNAME = %q, want %q", got, "My, tasks")
+	}
+
+	if len(calendar.Components) != 3 {
+		t.Fatalf("expected 3 components, got %d", len(calendar.Components))
+	}
+
+	expectProps := func(t *testing.T, component *ICSComponent, want map[string]string) {
+		t.Helper()
+
+		for name, value := range want {
+			if got := component.Text(name); got != value {
+				t.Errorf("%s %s = %q, want %q", component.Name, name, got, value)
+			}
+		}
+	}
+
+	t.Run("VTODO", func(t *testing.T) {
+		component := calendar.Components[0]
+
+		if component.Name != "VTODO" {
+			t.Fatalf("component = %s, want VTODO", component.Name)
+		}
+
+		expectProps(t, component, map[string]string{
+			"UID":           todo.Id.Hex() + "@tasker",
+			"DTSTAMP":       "20261002T093000Z",
+			"CREATED":       "20261001T080000Z",
+			"LAST-MODIFIED": "20261002T093000Z",
+			"SEQUENCE":      "3",
+			"SUMMARY":       longTitle,
+			"DESCRIPTION":   "first line\nsecond; with, separators",
+			"PRIORITY":      "1",
+			"DUE":           "20261101",
+			"STATUS":        "NEEDS-ACTION",
+		})
+
+		if got := component.Get("DUE").Params["VALUE"]; got != "DATE" {
+			t.Errorf("DUE VALUE = %q, want DATE", got)
+		}
+
+		if got := ICSCategories(component); !reflect.DeepEqual(got, []string{"work", "a,b"}) {
+			t.Errorf("categories = %q, want %q", got, []string{"work", "a,b"})
+		}
+
+		if len(component.Components) != 1 || component.Components[0].Name != "VALARM" {
+			t.Fatalf("expected a VALARM, got %#v", component.Components)
+		}
+
+		expectProps(t, component.Components[0], map[string]string{
+			"ACTION":      "DISPLAY",
+			"TRIGGER":     "-PT30M",
+			"DESCRIPTION": longTitle,
+		})
+	})
+
+	t.Run("completed VTODO", func(t *testing.T) {
+		component := calendar.Components[1]
+
+		expectProps(t, component, map[string]string{
+			"SUMMARY":          "Done task",
+			"STATUS":           "COMPLETED",
+			"PERCENT-COMPLETE": "100",
+			"COMPLETED":        "20261003T100000Z",
+		})
+
+		if component.Get("DUE") != nil {
+			t.Error("task without a date has a DUE property")
+		}
+
+		if len(component.Components) != 0 {
+			t.Error("task without a date has a VALARM")
+		}
+	})
+
+	t.Run("VEVENT", func(t *testing.T) {
+		component := calendar.Components[2]
+
+		if component.Name != "VEVENT" {
+			t.Fatalf("component = %s, want VEVENT", component.Name)
+		}
+
+		expectProps(t, component, map[string]string{
+			"DTSTART":  "20261102T190000Z",
+			"DTEND":    "20261102T200000Z",
+			"TRANSP":   "OPAQUE",
+			"PRIORITY": "9",
+		})
+
+		if component.Get("STATUS") != nil {
+			t.Error("VEVENT has a STATUS property")
+		}
+
+		start, err := ParseICSTime(component.Get("DTSTART"))
+
+		if err != nil || !start.Equal(from) {
+			t.Errorf("DTSTART = %v (%v), want %v", start, err, from)
+		}
+
+		if len(component.Components) != 1 || component.Components[0].Name != "VALARM" {
+			t.Fatalf("expected a VALARM, got %#v", component.Components)
+		}
+	})
+}
+
+func TestParseICS(t *testing.T) {
+	input := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"SUMMARY:Folded \r\n" +
+		" across\r\n" +
+		"\tlines\r\n" +
+		"DESCRIPTION;LANGUAGE=en:a\\nb\\, c\\;d\\\\\r\n" +
+		"DTSTART;TZID=\"Europe/Madrid\":20261101T090000\r\n" +
+		"X-URL;X-NOTE=\"a:b\":https://example.com\r\n" +
+		"CATEGORIES:one, two\\,three\r\n" +
+		"CATEGORIES:four\r\n" +
+		"end:vtodo\r\n" +
+		"END:VCALENDAR\n"
+
+	roots, err := ParseICS(strings.NewReader(input))
+
+	if err != nil {
+		t.Fatalf("ParseICS returned error: %v", err)
+	}
+
+	if len(roots) != 1 || len(roots[0].Components) != 1 {
+		t.Fatalf("unexpected structure: %#v", roots)
+	}
+
+	todo := roots[0].Components[0]
+
+	if got := todo.Text("SUMMARY"); got != "Folded acrosslines" {
+		t.Errorf("SUMMARY = %q, want %q", got, "Folded acrosslines")
+	}
+
+	if got := todo.Text("DESCRIPTION"); got != "a\nb, c;d\\" {
+		t.Errorf("DESCRIPTION = %q, want %q", got, "a\nb, c;d\\")
+	}
+
+	if got := todo.Get("DESCRIPTION").Params["LANGUAGE"]; got != "en" {
+		t.Errorf("LANGUAGE = %q, want en", got)
+	}
+
+	url := todo.Get("X-URL")
+
+	if url.Value != "https://example.com" || url.Params["X-NOTE"] != "a:b" {
+		t.Errorf("X-URL = %#v", url)
+	}
+
+	if got := ICSCategories(todo); !reflect.DeepEqual(got, []string{"one", "two,three", "four"}) {
+		t.Errorf("categories = %q", got)
+	}
+
+	madrid, err := time.LoadLocation("Europe/Madrid")
+
+	if err != nil {
+		t.Skipf("time zone data is not available: %v", err)
+	}
+
+	start, err := ParseICSTime(todo.Get("DTSTART"))
+
+	if err != nil || !start.Equal(time.Date(2026, 11, 1, 9, 0, 0, 0, madrid)) {
+		t.Errorf("DTSTART = %v (%v)", start, err)
+	}
+}
+
+func TestParseICSTime(t *testing.T) {
+	tests := []struct {
+		name string
+		prop ICSProperty
+		want time.Time
+	}{
+		{"date", ICSProperty{Params: map[string]string{"VALUE": "DATE"}, Value: "20261101"}, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"bare date", ICSProperty{Params: map[string]string{}, Value: "20261101"}, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"utc", ICSProperty{Params: map[string]string{}, Value: "20261101T093000Z"}, time.Date(2026, 11, 1, 9, 30, 0, 0, time.UTC)},
+		{"floating", ICSProperty{Params: map[string]string{}, Value: "20261101T093000"}, time.Date(2026, 11, 1, 9, 30, 0, 0, time.UTC)},
+		{"unknown zone", ICSProperty{Params: map[string]string{"TZID": "Nowhere/Special"}, Value: "20261101T093000"}, time.Date(2026, 11, 1, 9, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseICSTime(&tt.prop)
+
+			if err != nil {
+				t.Fatalf("ParseICSTime returned error: %v", err)
+			}
+
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseICSTime = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseICSTime(&ICSProperty{Value: "tomorrow"}); err == nil {
+		t.Error("expected an error for an invalid time")
+	}
+}
+
+func TestParseICSErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{"missing colon", "BEGIN:VCALENDAR\nSUMMARY\nEND:VCALENDAR\n", "invalid iCalendar line 2"},
+		{"mismatched end", "BEGIN:VCALENDAR\nBEGIN:VTODO\nEND:VCALENDAR\n", "unexpected END:VCALENDAR on line 3"},
+		{"end without begin", "END:VTODO\n", "unexpected END:VTODO on line 1"},
+		{"property outside component", "SUMMARY:x\n", "property outside of a component on line 1"},
+		{"missing end", "BEGIN:VCALENDAR\nBEGIN:VTODO\n", "missing END:VTODO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseICS(strings.NewReader(tt.input))
+
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("ParseICS error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
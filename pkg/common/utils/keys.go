@@ -0,0 +1,241 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type SigningKeyConfig struct {
+	Kid            string     `json:"kid"`
+	Algorithm      string     `json:"algorithm"`
+	PrivateKeyPath string     `json:"private_key_path"`
+	ActiveFrom     *time.Time `json:"active_from"`
+	RetireAt       *time.Time `json:"retire_at"`
+}
+
+type SigningKey struct {
+	Kid        string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.Signer
+	ActiveFrom time.Time
+	RetireAt   *time.Time
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+var signingKeys []SigningKey
+
+func LoadSigningKeys() {
+	path := os.Getenv("JWT_KEYS_FILE")
+
+	if path == "" {
+		log.Println("JWT_KEYS_FILE not set, falling back to HS256 tokens signed with API_SECRET")
+		return
+	}
+
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		log.Fatal("Error reading JWT keys file", err)
+	}
+
+	var configs []SigningKeyConfig
+
+	if err := json.Unmarshal(data, &configs); err != nil {
+		log.Fatal("Error parsing JWT keys file", err)
+	}
+
+	keys := make([]SigningKey, 0, len(configs))
+
+	for _, config := range configs {
+		keyPath := config.PrivateKeyPath
+
+		if !filepath.IsAbs(keyPath) {
+			keyPath = filepath.Join(filepath.Dir(path), keyPath)
+		}
+
+		key, err := loadSigningKey(config, keyPath)
+
+		if err != nil {
+			log.Fatal(fmt.Sprintf("Error loading JWT key '%s'", config.Kid), err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		log.Fatal("JWT keys file must contain at least one key")
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ActiveFrom.Before(keys[j].ActiveFrom)
+	})
+
+	signingKeys = keys
+}
+
+func loadSigningKey(config SigningKeyConfig, path string) (SigningKey, error) {
+	if config.Kid == "" {
+		return SigningKey{}, errors.New("kid is required")
+	}
+
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return SigningKey{}, err
+	}
+
+	block, _ := pem.Decode(data)
+
+	if block == nil {
+		return SigningKey{}, errors.New("private key is not PEM encoded")
+	}
+
+	var parsed interface{}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+
+	if err != nil {
+		return SigningKey{}, err
+	}
+
+	key := SigningKey{Kid: config.Kid, RetireAt: config.RetireAt}
+
+	if config.ActiveFrom != nil {
+		key.ActiveFrom = *config.ActiveFrom
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		switch config.Algorithm {
+		case "", "RS256":
+			key.Method = jwt.SigningMethodRS256
+		case "RS384":
+			key.Method = jwt.SigningMethodRS384
+		case "RS512":
+			key.Method = jwt.SigningMethodRS512
+		default:
+			return SigningKey{}, fmt.Errorf("algorithm '%s' can't be used with an RSA key", config.Algorithm)
+		}
+
+		key.PrivateKey = k
+	case ed25519.PrivateKey:
+		if config.Algorithm != "" && config.Algorithm != "EdDSA" {
+			return SigningKey{}, fmt.Errorf("algorithm '%s' can't be used with an Ed25519 key", config.Algorithm)
+		}
+
+		key.Method = jwt.SigningMethodEdDSA
+		key.PrivateKey = k
+	default:
+		return SigningKey{}, errors.New("unsupported private key type")
+	}
+
+	return key, nil
+}
+
+func currentSigningKey() (*SigningKey, error) {
+	now := time.Now()
+	var current *SigningKey
+
+	for i := range signingKeys {
+		key := &signingKeys[i]
+
+		if key.ActiveFrom.After(now) || (key.RetireAt != nil && !key.RetireAt.After(now)) {
+			continue
+		}
+
+		current = key
+	}
+
+	if current == nil {
+		return nil, errors.New("no active signing key")
+	}
+
+	return current, nil
+}
+
+func (key *SigningKey) verifiable(now time.Time) bool {
+	if key.RetireAt == nil {
+		return true
+	}
+
+	lifespan, _ := tokenLifespan()
+
+	return key.RetireAt.Add(lifespan).After(now)
+}
+
+func verificationKey(kid string) (*SigningKey, error) {
+	now := time.Now()
+
+	for i := range signingKeys {
+		key := &signingKeys[i]
+
+		if key.Kid != kid {
+			continue
+		}
+
+		if !key.verifiable(now) {
+			return nil, fmt.Errorf("signing key '%s' has been retired", kid)
+		}
+
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown signing key '%s'", kid)
+}
+
+func JWKS() []JWK {
+	now := time.Now()
+	keys := []JWK{}
+
+	for _, key := range signingKeys {
+		if !key.verifiable(now) {
+			continue
+		}
+
+		jwk := JWK{Use: "sig", Alg: key.Method.Alg(), Kid: key.Kid}
+
+		switch pub := key.PrivateKey.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+
+		keys = append(keys, jwk)
+	}
+
+	return keys
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetPagination(c *gin.Context) (int, int, []ErrorMsg) {
+	pageParam := c.Query("page")
+	pageSizeParam := c.Query("page_size")
+	queryParamsErrors := []ErrorMsg{}
+
+	if pageParam == "" {
+		queryParamsErrors = append(queryParamsErrors, ErrorMsg{
+			Field:   "page",
+			Message: "this query param is required",
+		})
+	}
+
+	page, err := strconv.Atoi(pageParam)
+
+	if err != nil {
+		queryParamsErrors = append(queryParamsErrors, ErrorMsg{
+			Field:   "page",
+			Message: "this query param must be a number",
+		})
+	}
+
+	if page < 1 {
+		queryParamsErrors = append(queryParamsErrors, ErrorMsg{
+			Field:   "page",
+			Message: "this query param must be greater than 0",
+		})
+	}
+
+	if pageSizeParam == "" {
+		queryParamsErrors = append(queryParamsErrors, ErrorMsg{
+			Field:   "page_size",
+			Message: "this query param is required",
+		})
+	}
+
+	pageSize, err := strconv.Atoi(pageSizeParam)
+
+	if err != nil {
+		queryParamsErrors = append(queryParamsErrors, ErrorMsg{
+			Field:   "page_size",
+			Message: "this query param must be a number",
+		})
+	}
+
+	if pageSize < 1 {
+		queryParamsErrors = append(queryParamsErrors, ErrorMsg{
+			Field:   "page_size",
+			Message: "this query param must be greater than 0",
+		})
+	}
+
+	return page, pageSize, queryParamsErrors
+}
+
+func GetPaginationInfo(page int, pageSize int, count int, totalRecords int64) gin.H {
+	totalPages := int(math.Ceil(float64(totalRecords) / float64(pageSize)))
+
+	var nextPage *int
+	var prevPage *int
+
+	if page < totalPages {
+		p := page + 1
+		nextPage = &p
+	}
+
+	if page > 1 && page <= totalPages {
+		p := page - 1
+		prevPage = &p
+	}
+
+	return gin.H{
+		"count":         count,
+		"page":          page,
+		"page_size":     pageSize,
+		"total_records": totalRecords,
+		"total_pages":   totalPages,
+		"next_page":     nextPage,
+		"prev_page":     prevPage,
+	}
+}
@@ -0,0 +1,26 @@
+package utils
+
+import "github.com/gin-gonic/gin"
+
+const (
+	AccessTokenPrefix = "tkr_"
+	UserIdKey         = "user_id"
+	ScopesKey         = "scopes"
+	UserKey           = "user"
+)
+
+func HasScope(c *gin.Context, scope string) bool {
+	value, ok := c.Get(ScopesKey)
+
+	if !ok {
+		return true
+	}
+
+	for _, s := range value.([]string) {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"os"
 	"strconv"
@@ -14,37 +15,111 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+func tokenLifespan() (time.Duration, error) {
+	hours, err := strconv.Atoi(os.Getenv("TOKEN_HOUR_LIFESPAN"))
+
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Hour * time.Duration(hours), nil
+}
+
 func GenerateToken(userId string) (string, error) {
-	lifespan, err := strconv.Atoi(os.Getenv("TOKEN_HOUR_LIFESPAN"))
+	lifespan, err := tokenLifespan()
 
 	if err != nil {
 		return "", err
 	}
 
-	claims := jwt.MapClaims{}
-	claims["authorized"] = true
-	claims["user_id"] = userId
-	claims["exp"] = time.Now().Add(time.Hour * time.Duration(lifespan)).Unix()
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	jti, err := GetRandomString(16)
+
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+
+	claims := jwt.MapClaims{
+		"sub": userId,
+		"iat": now.Unix(),
+		"exp": now.Add(lifespan).Unix(),
+		"jti": jti,
+	}
+
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		claims["iss"] = issuer
+	}
+
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		claims["aud"] = audience
+	}
 
-	return token.SignedString([]byte(os.Getenv("API_SECRET")))
+	if len(signingKeys) == 0 {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(os.Getenv("API_SECRET")))
+	}
+
+	key, err := currentSigningKey()
+
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.Kid
+
+	return token.SignedString(key.PrivateKey)
 }
 
-func TokenValid(c *gin.Context) error {
-	tokenString := ExtractToken(c)
-	_, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+func ParseToken(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if len(signingKeys) == 0 {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+
+			return []byte(os.Getenv("API_SECRET")), nil
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		key, err := verificationKey(kid)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if token.Method.Alg() != key.Method.Alg() {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 
-		return []byte(os.Getenv("API_SECRET")), nil
+		return key.PrivateKey.Public(), nil
 	})
 
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" && !claims.VerifyIssuer(issuer, true) {
+		return nil, errors.New("invalid token issuer")
+	}
+
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" && !claims.VerifyAudience(audience, true) {
+		return nil, errors.New("invalid token audience")
 	}
 
-	return nil
+	return claims, nil
+}
+
+func TokenValid(c *gin.Context) error {
+	_, err := ParseToken(ExtractToken(c))
+	return err
 }
 
 func ExtractToken(c *gin.Context) string {
@@ -58,34 +133,39 @@ func ExtractToken(c *gin.Context) string {
 }
 
 func ExtractTokenID(c *gin.Context) (*primitive.ObjectID, error) {
-	tokenString := ExtractToken(c)
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
+	if value, ok := c.Get(UserIdKey); ok {
+		return value.(*primitive.ObjectID), nil
+	}
 
-		return []byte(os.Getenv("API_SECRET")), nil
-	})
+	claims, err := ParseToken(ExtractToken(c))
 
 	if err != nil {
 		return nil, err
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
+	uid, ok := claims["sub"].(string)
 
-	if ok && token.Valid {
-		uid := fmt.Sprintf("%v", claims["user_id"])
+	if !ok {
+		uid = fmt.Sprintf("%v", claims["user_id"])
+	}
 
-		objId, err := primitive.ObjectIDFromHex(uid)
+	objId, err := primitive.ObjectIDFromHex(uid)
 
-		if err != nil {
-			return nil, err
-		}
+	if err != nil {
+		return nil, err
+	}
+
+	return &objId, nil
+}
+
+func GetRandomString(length int) (string, error) {
+	buffer := make([]byte, length)
 
-		return &objId, nil
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
 	}
 
-	return nil, errors.New("invalid token")
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
 }
 
 func GetOTPToken(length int) (string, error) {
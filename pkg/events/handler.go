@@ -0,0 +1,34 @@
+package events
+
+import (
+	"github.com/Bryan-an/tasker-backend/pkg/common/middlewares"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type handler struct {
+	DB     *mongo.Database
+	Client *mongo.Client
+}
+
+func RegisterRoutes(r *gin.Engine, db *mongo.Database, client *mongo.Client) {
+	h := &handler{
+		DB:     db,
+		Client: client,
+	}
+
+	r.POST(
+		"/api/v1/events/tickets",
+		middlewares.JwtAuthMiddleware(db),
+		middlewares.RequireScopes("tasks:read", "tasks:read"),
+		h.CreateTicket,
+	)
+
+	routes := r.Group("/api/v1/events")
+
+	routes.Use(ticketMiddleware(db))
+	routes.Use(middlewares.JwtAuthMiddleware(db))
+	routes.Use(middlewares.RequireScopes("tasks:read", "tasks:read"))
+	routes.GET("/", h.StreamEvents)
+	routes.GET("/ws", h.StreamEventsWebSocket)
+}
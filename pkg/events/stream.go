@@ -0,0 +1,174 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/events"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+func (h handler) StreamEvents(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	lastEventId := c.GetHeader("Last-Event-ID")
+
+	if lastEventId == "" {
+		lastEventId = c.Query("last_event_id")
+	}
+
+	sub := events.Subscribe(*uid, lastEventId)
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if sub.Resync {
+		writeSSE(c, events.Event{Type: events.Resync, CreatedAt: time.Now()})
+	}
+
+	for _, event := range sub.Replay {
+		if allowed(c, event) {
+			writeSSE(c, event)
+		}
+	}
+
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval())
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+
+			if allowed(c, event) {
+				writeSSE(c, event)
+				c.Writer.Flush()
+			}
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+func (h handler) StreamEventsWebSocket(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	server := websocket.Server{
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			sub := events.Subscribe(*uid, c.Query("last_event_id"))
+			defer sub.Close()
+
+			closed := make(chan struct{})
+
+			go func() {
+				defer close(closed)
+				var message string
+
+				for websocket.Message.Receive(ws, &message) == nil {
+				}
+			}()
+
+			if sub.Resync {
+				if websocket.JSON.Send(ws, events.Event{Type: events.Resync, CreatedAt: time.Now()}) != nil {
+					return
+				}
+			}
+
+			for _, event := range sub.Replay {
+				if allowed(c, event) && websocket.JSON.Send(ws, event) != nil {
+					return
+				}
+			}
+
+			heartbeat := time.NewTicker(heartbeatInterval())
+			defer heartbeat.Stop()
+
+			for {
+				select {
+				case <-closed:
+					return
+				case event, ok := <-sub.Events:
+					if !ok {
+						return
+					}
+
+					if allowed(c, event) && websocket.JSON.Send(ws, event) != nil {
+						return
+					}
+				case <-heartbeat.C:
+					if websocket.JSON.Send(ws, gin.H{"type": "heartbeat"}) != nil {
+						return
+					}
+				}
+			}
+		},
+	}
+
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+func writeSSE(c *gin.Context, event events.Event) {
+	data, err := json.Marshal(event)
+
+	if err != nil {
+		return
+	}
+
+	if event.Id != "" {
+		fmt.Fprintf(c.Writer, "id: %s\n", event.Id)
+	}
+
+	fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event.Type, data)
+}
+
+func allowed(c *gin.Context, event events.Event) bool {
+	if strings.HasPrefix(event.Type, "settings.") {
+		return utils.HasScope(c, "settings:read")
+	}
+
+	if strings.HasPrefix(event.Type, "task.") {
+		return utils.HasScope(c, "tasks:read")
+	}
+
+	return true
+}
+
+func heartbeatInterval() time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv("EVENTS_HEARTBEAT_SECONDS"))
+
+	if err != nil || seconds < 1 {
+		seconds = 25
+	}
+
+	return time.Second * time.Duration(seconds)
+}
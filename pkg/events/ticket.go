@@ -0,0 +1,103 @@
+package events
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (h handler) CreateTicket(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	ticket, err := utils.GetRandomString(32)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ticketLifespan())
+	hash := utils.HashToken(ticket)
+
+	t := models.StreamTicket{
+		UserId:    uid,
+		Hash:      &hash,
+		ExpiresAt: &expiresAt,
+		CreatedAt: &now,
+	}
+
+	if value, ok := c.Get(utils.ScopesKey); ok {
+		scopes := value.([]string)
+		t.Scopes = &scopes
+	}
+
+	if _, err := h.DB.Collection("stream_tickets").InsertOne(context.TODO(), t); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"ticket":     ticket,
+		"expires_at": expiresAt,
+	})
+}
+
+func ticketMiddleware(db *mongo.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ticket := c.Query("ticket")
+
+		if ticket == "" || c.GetHeader("Authorization") != "" {
+			c.Next()
+			return
+		}
+
+		filter := bson.D{
+			{Key: "hash", Value: utils.HashToken(ticket)},
+			{Key: "expires_at", Value: bson.D{{Key: "$gt", Value: time.Now()}}},
+		}
+
+		var t models.StreamTicket
+
+		if err := db.Collection("stream_tickets").FindOneAndDelete(context.TODO(), filter).Decode(&t); err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+				return
+			}
+
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.Set(utils.UserIdKey, t.UserId)
+
+		if t.Scopes != nil {
+			c.Set(utils.ScopesKey, *t.Scopes)
+		}
+
+		c.Next()
+	}
+}
+
+func ticketLifespan() time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv("EVENTS_TICKET_EXPIRATION_SECONDS"))
+
+	if err != nil || seconds < 1 {
+		seconds = 60
+	}
+
+	return time.Second * time.Duration(seconds)
+}
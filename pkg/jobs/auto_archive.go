@@ -0,0 +1,121 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/events"
+	"github.com/Bryan-an/tasker-backend/pkg/common/history"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func autoArchiveTasks(ctx context.Context, db *mongo.Database) error {
+	settingsFilter := bson.D{{Key: "auto_archive_days", Value: bson.D{{Key: "$gt", Value: 0}}}}
+	projection := bson.D{{Key: "user_id", Value: 1}, {Key: "auto_archive_days", Value: 1}}
+	var settings []models.Settings
+
+	if err := findAll(ctx, db.Collection("settings"), settingsFilter, projection, &settings); err != nil {
+		return err
+	}
+
+	tasksCollection := db.Collection("tasks")
+	var archived int64
+
+	for _, s := range settings {
+		now := time.Now()
+		cutoff := bson.D{{Key: "$lte", Value: now.AddDate(0, 0, -*s.AutoArchiveDays)}}
+
+		filter := bson.D{
+			{Key: "user_id", Value: s.UserId},
+			{Key: "status", Value: "created"},
+			{Key: "done", Value: true},
+			{Key: "$or", Value: bson.A{
+				bson.D{{Key: "done_at", Value: cutoff}},
+				bson.D{
+					{Key: "done_at", Value: nil},
+					{Key: "updated_at", Value: cutoff},
+				},
+			}},
+		}
+
+		var tasks []models.Task
+
+		if err := findAll(ctx, tasksCollection, filter, bson.D{{Key: "_id", Value: 1}}, &tasks); err != nil {
+			return err
+		}
+
+		if len(tasks) == 0 {
+			continue
+		}
+
+		ids := make([]primitive.ObjectID, 0, len(tasks))
+
+		for _, task := range tasks {
+			ids = append(ids, *task.Id)
+		}
+
+		update := bson.D{
+			{
+				Key: "$set",
+				Value: bson.D{
+					{Key: "status", Value: "archived"},
+					{Key: "archived_at", Value: now},
+					{Key: "updated_at", Value: now},
+				},
+			},
+			{
+				Key:   "$inc",
+				Value: bson.D{{Key: "version", Value: 1}},
+			},
+		}
+
+		idsFilter := bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}},
+			{Key: "status", Value: "created"},
+		}
+
+		result, err := tasksCollection.UpdateMany(ctx, idsFilter, update)
+
+		if err != nil {
+			return err
+		}
+
+		archived += result.ModifiedCount
+
+		action := models.TaskActionArchived
+		source := "job: auto archive done tasks"
+		entries := make([]models.TaskHistory, 0, len(ids))
+
+		for i := range ids {
+			entries = append(entries, models.TaskHistory{
+				TaskId:  &ids[i],
+				UserId:  s.UserId,
+				Action:  &action,
+				Source:  &source,
+				Changes: []models.FieldChange{{Field: "status", Old: "created", New: "archived"}},
+			})
+		}
+
+		if err := history.RecordMany(ctx, db, entries); err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			events.Publish(*s.UserId, events.TaskEvent(action), events.TaskChange{
+				TaskId:  *entry.TaskId,
+				Action:  action,
+				Changes: entry.Changes,
+			})
+		}
+	}
+
+	if archived > 0 {
+		log.Printf("%d done task(s) archived automatically", archived)
+	}
+
+	return nil
+}
@@ -0,0 +1,348 @@
+package jobs
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/history"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/timetracking"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func ExportsDir() string {
+	if dir := os.Getenv("EXPORTS_DIR"); dir != "" {
+		return dir
+	}
+
+	return "exports"
+}
+
+func ProcessDataExports(ctx context.Context, db *mongo.Database) error {
+	coll := db.Collection("exports")
+
+	for {
+		filter := bson.D{
+			{Key: "$or", Value: bson.A{
+				bson.D{{Key: "status", Value: "pending"}},
+				bson.D{
+					{Key: "status", Value: "processing"},
+					{Key: "updated_at", Value: bson.D{{Key: "$lt", Value: time.Now().Add(-time.Hour)}}},
+				},
+			}},
+		}
+
+		update := bson.D{
+			{
+				Key: "$set",
+				Value: bson.D{
+					{Key: "status", Value: "processing"},
+					{Key: "updated_at", Value: time.Now()},
+				},
+			},
+		}
+
+		opts := options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "created_at", Value: 1}}).
+			SetReturnDocument(options.After)
+
+		var export models.Export
+
+		if err := coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&export); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil
+			}
+
+			return err
+		}
+
+		if err := processDataExport(ctx, db, export); err != nil {
+			log.Printf("Data export '%s' failed: %s", export.Id.Hex(), err.Error())
+
+			failed := bson.D{
+				{
+					Key: "$set",
+					Value: bson.D{
+						{Key: "status", Value: "failed"},
+						{Key: "error", Value: "error occurred while generating the export"},
+						{Key: "updated_at", Value: time.Now()},
+					},
+				},
+			}
+
+			if _, err := coll.UpdateByID(ctx, export.Id, failed); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func processDataExport(ctx context.Context, db *mongo.Database, export models.Export) error {
+	var user models.User
+	opts := options.FindOne().SetProjection(bson.D{{Key: "password", Value: 0}})
+
+	if err := db.Collection("users").FindOne(ctx, bson.D{{Key: "_id", Value: export.UserId}}, opts).Decode(&user); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(ExportsDir(), 0o700); err != nil {
+		return err
+	}
+
+	path := filepath.Join(ExportsDir(), export.Id.Hex()+".zip")
+
+	if err := writeDataExport(ctx, db, user, path); err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	token, err := utils.GetRandomString(32)
+
+	if err != nil {
+		return err
+	}
+
+	hours, err := strconv.Atoi(os.Getenv("EXPORT_LINK_EXPIRATION_HOURS"))
+
+	if err != nil {
+		hours = 48
+	}
+
+	tokenHash := utils.HashToken(token)
+	expiresAt := time.Now().Add(time.Hour * time.Duration(hours))
+
+	update := bson.D{
+		{
+			Key: "$set",
+			Value: bson.D{
+				{Key: "status", Value: "ready"},
+				{Key: "token_hash", Value: tokenHash},
+				{Key: "file_path", Value: path},
+				{Key: "expires_at", Value: expiresAt},
+				{Key: "updated_at", Value: time.Now()},
+			},
+		},
+	}
+
+	if _, err := db.Collection("exports").UpdateByID(ctx, export.Id, update); err != nil {
+		return err
+	}
+
+	link := strings.TrimSuffix(os.Getenv("API_URL"), "/") + "/api/v1/users/export/download/" + token
+
+	return utils.SendEmail(
+		*user.Email,
+		"Tasker - Your data export is ready",
+		"<p>Your Tasker data export is ready. <a href=\""+link+"\">Download it here</a>.</p>"+
+			"<p>The link expires on "+expiresAt.Format(time.RFC1123)+".</p>",
+	)
+}
+
+func writeDataExport(ctx context.Context, db *mongo.Database, user models.User, path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	userFilter := bson.D{{Key: "user_id", Value: user.Id}}
+
+	if err := writeJSON(archive, "profile.json", user); err != nil {
+		return err
+	}
+
+	var settings []models.Settings
+
+	if err := findAll(ctx, db.Collection("settings"), userFilter, nil, &settings); err != nil {
+		return err
+	}
+
+	if err := writeJSON(archive, "settings.json", settings); err != nil {
+		return err
+	}
+
+	var tasks []models.Task
+
+	if err := findAll(ctx, db.Collection("tasks"), userFilter, nil, &tasks); err != nil {
+		return err
+	}
+
+	if err := writeJSON(archive, "tasks.json", tasks); err != nil {
+		return err
+	}
+
+	if err := writeTasksCSV(archive, tasks); err != nil {
+		return err
+	}
+
+	var entries []models.TaskHistory
+
+	if err := findAll(ctx, db.Collection(history.Collection), userFilter, nil, &entries); err != nil {
+		return err
+	}
+
+	if err := writeJSON(archive, "task_history.json", entries); err != nil {
+		return err
+	}
+
+	var webhooks []models.Webhook
+
+	if err := findAll(ctx, db.Collection("webhooks"), userFilter, nil, &webhooks); err != nil {
+		return err
+	}
+
+	if err := writeJSON(archive, "webhooks.json", webhooks); err != nil {
+		return err
+	}
+
+	var labels []models.Label
+
+	if err := findAll(ctx, db.Collection("labels"), userFilter, nil, &labels); err != nil {
+		return err
+	}
+
+	if err := writeJSON(archive, "labels.json", labels); err != nil {
+		return err
+	}
+
+	var filters []models.SavedFilter
+
+	if err := findAll(ctx, db.Collection("saved_filters"), userFilter, nil, &filters); err != nil {
+		return err
+	}
+
+	if err := writeJSON(archive, "saved_filters.json", filters); err != nil {
+		return err
+	}
+
+	var timeEntries []models.TimeEntry
+
+	if err := findAll(ctx, db.Collection(timetracking.Collection), userFilter, nil, &timeEntries); err != nil {
+		return err
+	}
+
+	if err := writeJSON(archive, "time_entries.json", timeEntries); err != nil {
+		return err
+	}
+
+	var feeds []models.CalendarFeed
+
+	if err := findAll(ctx, db.Collection("calendar_feeds"), userFilter, nil, &feeds); err != nil {
+		return err
+	}
+
+	if err := writeJSON(archive, "calendar_feeds.json", feeds); err != nil {
+		return err
+	}
+
+	var tokens []models.AccessToken
+	tokenProjection := bson.D{{Key: "hash", Value: 0}}
+
+	if err := findAll(ctx, db.Collection("access_tokens"), userFilter, tokenProjection, &tokens); err != nil {
+		return err
+	}
+
+	if err := writeJSON(archive, "access_tokens.json", tokens); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+func findAll(ctx context.Context, coll *mongo.Collection, filter bson.D, projection bson.D, results interface{}) error {
+	opts := options.Find()
+
+	if projection != nil {
+		opts.SetProjection(projection)
+	}
+
+	cursor, err := coll.Find(ctx, filter, opts)
+
+	if err != nil {
+		return err
+	}
+
+	return cursor.All(ctx, results)
+}
+
+func writeJSON(archive *zip.Writer, name string, data interface{}) error {
+	w, err := archive.Create(name)
+
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(data)
+}
+
+func writeTasksCSV(archive *zip.Writer, tasks []models.Task) error {
+	w, err := archive.Create("tasks.csv")
+
+	if err != nil {
+		return err
+	}
+
+	return utils.WriteTasksCSV(w, tasks)
+}
+
+func expireDataExports(ctx context.Context, db *mongo.Database) error {
+	coll := db.Collection("exports")
+
+	filter := bson.D{
+		{Key: "status", Value: "ready"},
+		{Key: "expires_at", Value: bson.D{{Key: "$lte", Value: time.Now()}}},
+	}
+
+	var exports []models.Export
+
+	if err := findAll(ctx, coll, filter, nil, &exports); err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		if export.FilePath != nil {
+			if err := os.Remove(*export.FilePath); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+
+		update := bson.D{
+			{
+				Key: "$set",
+				Value: bson.D{
+					{Key: "status", Value: "expired"},
+					{Key: "updated_at", Value: time.Now()},
+				},
+			},
+			{
+				Key: "$unset",
+				Value: bson.D{
+					{Key: "token_hash", Value: ""},
+					{Key: "file_path", Value: ""},
+				},
+			},
+		}
+
+		if _, err := coll.UpdateByID(ctx, export.Id, update); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,790 @@
+package jobs
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/events"
+	"github.com/Bryan-an/tasker-backend/pkg/common/history"
+	"github.com/Bryan-an/tasker-backend/pkg/common/labels"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ImportFields = map[string]string{
+	"title":       "Title",
+	"description": "Description",
+	"labels":      "Labels",
+	"priority":    "Priority",
+	"complexity":  "Complexity",
+	"date":        "Date",
+	"from":        "From",
+	"to":          "To",
+	"done":        "Done",
+	"remind":      "Remind",
+}
+
+var importTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"01/02/2006",
+}
+
+type importInput struct {
+	Title       *string `binding:"required"`
+	Description *string
+	Labels      *[]string
+	Priority    *string    `binding:"required,oneof=low medium high"`
+	Complexity  *string    `binding:"required,oneof=low medium high"`
+	Date        *time.Time `binding:"required"`
+	From        *time.Time
+	To          *time.Time
+	Done        *bool `binding:"required"`
+	Remind      *bool `binding:"required"`
+	DoneAt      *time.Time
+}
+
+type importCandidate struct {
+	Row    int
+	Uid    string
+	Input  importInput
+	Errors []utils.ErrorMsg
+}
+
+func ImportsDir() string {
+	if dir := os.Getenv("IMPORTS_DIR"); dir != "" {
+		return dir
+	}
+
+	return "imports"
+}
+
+func ImportMaxBytes() int64 {
+	size, err := strconv.ParseInt(os.Getenv("IMPORT_MAX_BYTES"), 10, 64)
+
+	if err != nil || size < 1 {
+		size = 5 << 20
+	}
+
+	return size
+}
+
+func importMaxRows() int {
+	rows, err := strconv.Atoi(os.Getenv("IMPORT_MAX_ROWS"))
+
+	if err != nil || rows < 1 {
+		rows = 1000
+	}
+
+	return rows
+}
+
+func ProcessImports(ctx context.Context, db *mongo.Database) error {
+	coll := db.Collection("imports")
+
+	for {
+		filter := bson.D{
+			{Key: "$or", Value: bson.A{
+				bson.D{{Key: "status", Value: "pending"}},
+				bson.D{
+					{Key: "status", Value: "processing"},
+					{Key: "updated_at", Value: bson.D{{Key: "$lt", Value: time.Now().Add(-time.Hour)}}},
+				},
+			}},
+		}
+
+		update := bson.D{
+			{
+				Key: "$set",
+				Value: bson.D{
+					{Key: "status", Value: "processing"},
+					{Key: "updated_at", Value: time.Now()},
+				},
+			},
+		}
+
+		opts := options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "created_at", Value: 1}}).
+			SetReturnDocument(options.After)
+
+		var imp models.Import
+
+		if err := coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&imp); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil
+			}
+
+			return err
+		}
+
+		if err := processImport(ctx, db, imp); err != nil {
+			log.Printf("Import '%s' failed: %s", imp.Id.Hex(), err.Error())
+
+			var message string
+
+			if errors.Is(err, errInvalidImport) {
+				message = strings.TrimPrefix(err.Error(), errInvalidImport.Error()+": ")
+			} else {
+				message = "error occurred while processing the import"
+			}
+
+			removeImportFile(imp)
+
+			failed := bson.D{
+				{
+					Key: "$set",
+					Value: bson.D{
+						{Key: "status", Value: "failed"},
+						{Key: "error", Value: message},
+						{Key: "updated_at", Value: time.Now()},
+					},
+				},
+				{Key: "$unset", Value: bson.D{{Key: "file_path", Value: ""}}},
+			}
+
+			if _, err := coll.UpdateByID(ctx, imp.Id, failed); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+var errInvalidImport = errors.New("invalid import")
+
+func invalidImport(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", errInvalidImport, fmt.Sprintf(format, args...))
+}
+
+func processImport(ctx context.Context, db *mongo.Database, imp models.Import) error {
+	if imp.FilePath == nil {
+		return invalidImport("the uploaded file is no longer available")
+	}
+
+	file, err := os.Open(*imp.FilePath)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return invalidImport("the uploaded file is no longer available")
+		}
+
+		return err
+	}
+
+	defer file.Close()
+
+	var candidates []importCandidate
+
+	if *imp.Format == models.ImportFormatICS {
+		candidates, err = parseICSImport(file)
+	} else {
+		candidates, err = parseCSVImport(file, imp.Mapping)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(candidates) > importMaxRows() {
+		return invalidImport("the file contains more than %d tasks", importMaxRows())
+	}
+
+	dryRun := imp.DryRun != nil && *imp.DryRun
+	summary, rows, err := importTasks(ctx, db, *imp.UserId, candidates, dryRun)
+
+	if err != nil {
+		return err
+	}
+
+	set := bson.D{
+		{Key: "status", Value: "completed"},
+		{Key: "summary", Value: summary},
+		{Key: "rows", Value: rows},
+		{Key: "updated_at", Value: time.Now()},
+	}
+
+	update := bson.D{{Key: "$set", Value: set}}
+
+	if !dryRun {
+		file.Close()
+		removeImportFile(imp)
+		update = append(update, bson.E{Key: "$unset", Value: bson.D{{Key: "file_path", Value: ""}}})
+	}
+
+	_, err = db.Collection("imports").UpdateByID(ctx, imp.Id, update)
+	return err
+}
+
+func importTasks(ctx context.Context, db *mongo.Database, uid primitive.ObjectID, candidates []importCandidate, dryRun bool) (models.ImportSummary, []models.ImportRow, error) {
+	tasksCollection := db.Collection("tasks")
+	summary := models.ImportSummary{Total: len(candidates)}
+	rows := make([]models.ImportRow, 0, len(candidates))
+	seen := map[string]bool{}
+	var entries []models.TaskHistory
+
+	for _, candidate := range candidates {
+		input := candidate.Input
+		row := models.ImportRow{Row: candidate.Row, Title: input.Title, Errors: candidate.Errors}
+
+		if len(row.Errors) == 0 {
+			if err := binding.Validator.ValidateStruct(&input); err != nil {
+				var ve validator.ValidationErrors
+
+				if !errors.As(err, &ve) {
+					return summary, nil, err
+				}
+
+				row.Errors = utils.FillErrors(ve)
+			}
+		}
+
+		if len(row.Errors) > 0 {
+			row.Status = models.ImportRowInvalid
+			summary.Invalid++
+			rows = append(rows, row)
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSpace(*input.Title)) + "|" + input.Date.UTC().Format("2006-01-02")
+
+		if seen[key] {
+			reason := "duplicate of an earlier row in the file"
+			row.Status = models.ImportRowSkipped
+			row.Reason = &reason
+			summary.Skipped++
+			rows = append(rows, row)
+			continue
+		}
+
+		seen[key] = true
+		duplicate, err := isDuplicateImport(ctx, tasksCollection, uid, candidate)
+
+		if err != nil {
+			return summary, nil, err
+		}
+
+		if duplicate {
+			reason := "a task with the same title and date already exists"
+			row.Status = models.ImportRowSkipped
+			row.Reason = &reason
+			summary.Skipped++
+			rows = append(rows, row)
+			continue
+		}
+
+		summary.Valid++
+
+		if dryRun {
+			row.Status = models.ImportRowReady
+			rows = append(rows, row)
+			continue
+		}
+
+		t := newImportedTask(uid, input)
+		req, err := tasksCollection.InsertOne(ctx, t)
+
+		if err != nil {
+			return summary, nil, err
+		}
+
+		id := req.InsertedID.(primitive.ObjectID)
+
+		if input.Labels != nil {
+			if err := labels.Ensure(ctx, db, uid, *input.Labels); err != nil {
+				return summary, nil, err
+			}
+		}
+
+		fields, err := history.ToMap(t)
+
+		if err != nil {
+			return summary, nil, err
+		}
+
+		action := models.TaskActionCreated
+		source := "job: import tasks"
+
+		entries = append(entries, models.TaskHistory{
+			TaskId:  &id,
+			UserId:  &uid,
+			Action:  &action,
+			Source:  &source,
+			Changes: history.Diff(bson.M{}, fields),
+		})
+
+		row.Status = models.ImportRowCreated
+		row.TaskId = &id
+		summary.Created++
+		rows = append(rows, row)
+	}
+
+	if err := history.RecordMany(ctx, db, entries); err != nil {
+		return summary, nil, err
+	}
+
+	for _, entry := range entries {
+		events.Publish(uid, events.TaskEvent(*entry.Action), events.TaskChange{
+			TaskId:  *entry.TaskId,
+			Action:  *entry.Action,
+			Changes: entry.Changes,
+		})
+	}
+
+	return summary, rows, nil
+}
+
+func isDuplicateImport(ctx context.Context, coll *mongo.Collection, uid primitive.ObjectID, candidate importCandidate) (bool, error) {
+	statuses := bson.D{{Key: "$in", Value: bson.A{"created", "archived"}}}
+
+	if strings.HasSuffix(candidate.Uid, "@tasker") {
+		if id, err := primitive.ObjectIDFromHex(strings.TrimSuffix(candidate.Uid, "@tasker")); err == nil {
+			filter := bson.D{
+				{Key: "_id", Value: id},
+				{Key: "user_id", Value: uid},
+				{Key: "status", Value: statuses},
+			}
+
+			count, err := coll.CountDocuments(ctx, filter)
+
+			if err != nil || count > 0 {
+				return count > 0, err
+			}
+		}
+	}
+
+	day := candidate.Input.Date.UTC().Truncate(24 * time.Hour)
+	title := regexp.QuoteMeta(strings.TrimSpace(*candidate.Input.Title))
+
+	filter := bson.D{
+		{Key: "user_id", Value: uid},
+		{Key: "status", Value: statuses},
+		{Key: "title", Value: primitive.Regex{Pattern: "^" + title + "$", Options: "i"}},
+		{Key: "date", Value: bson.D{
+			{Key: "$gte", Value: day},
+			{Key: "$lt", Value: day.Add(24 * time.Hour)},
+		}},
+	}
+
+	count, err := coll.CountDocuments(ctx, filter)
+
+	return count > 0, err
+}
+
+func newImportedTask(uid primitive.ObjectID, input importInput) models.Task {
+	status := "created"
+	now := time.Now()
+	version := int64(1)
+	var doneAt *time.Time
+
+	if *input.Done {
+		doneAt = input.DoneAt
+
+		if doneAt == nil {
+			doneAt = &now
+		}
+	}
+
+	return models.Task{
+		UserId:      &uid,
+		Title:       input.Title,
+		Description: input.Description,
+		Labels:      input.Labels,
+		Priority:    input.Priority,
+		Complexity:  input.Complexity,
+		Date:        input.Date,
+		From:        input.From,
+		To:          input.To,
+		Done:        input.Done,
+		DoneAt:      doneAt,
+		Remind:      input.Remind,
+		Status:      &status,
+		Version:     &version,
+		CreatedAt:   &now,
+		UpdatedAt:   &now,
+	}
+}
+
+func parseCSVImport(r io.Reader, mapping map[string]string) ([]importCandidate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+
+	if err == io.EOF {
+		return nil, invalidImport("the CSV file is empty")
+	}
+
+	if err != nil {
+		return nil, invalidImport("the CSV file could not be read: %s", err.Error())
+	}
+
+	index := map[string]int{}
+
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	columns := map[string]int{}
+
+	for field := range ImportFields {
+		column := field
+
+		if mapped, ok := mapping[field]; ok {
+			column = mapped
+		}
+
+		i, ok := index[strings.ToLower(strings.TrimSpace(column))]
+
+		if !ok {
+			if _, mapped := mapping[field]; mapped {
+				return nil, invalidImport("column '%s' not found in the CSV header", column)
+			}
+
+			continue
+		}
+
+		columns[field] = i
+	}
+
+	if _, ok := columns["title"]; !ok {
+		return nil, invalidImport("the CSV file has no title column")
+	}
+
+	var candidates []importCandidate
+
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, invalidImport("the CSV file could not be read: %s", err.Error())
+		}
+
+		values := map[string]string{}
+		empty := true
+
+		for field, i := range columns {
+			if i < len(record) {
+				values[field] = strings.TrimSpace(record[i])
+
+				if values[field] != "" {
+					empty = false
+				}
+			}
+		}
+
+		if empty {
+			continue
+		}
+
+		candidate := importCandidate{Row: row}
+		input := &candidate.Input
+		input.Title = importString(values["title"])
+		input.Description = importString(values["description"])
+		input.Priority = importLevel(values["priority"])
+		input.Complexity = importLevel(values["complexity"])
+
+		if labels := importLabels(values["labels"]); len(labels) > 0 {
+			input.Labels = &labels
+		}
+
+		for _, field := range []string{"date", "from", "to"} {
+			if values[field] == "" {
+				continue
+			}
+
+			t, err := parseImportTime(values[field])
+
+			if err != nil {
+				candidate.Errors = append(candidate.Errors, utils.ErrorMsg{
+					Field:   ImportFields[field],
+					Message: "this field must be a valid date",
+				})
+
+				continue
+			}
+
+			switch field {
+			case "date":
+				input.Date = t
+			case "from":
+				input.From = t
+			case "to":
+				input.To = t
+			}
+		}
+
+		for _, field := range []string{"done", "remind"} {
+			value, err := parseImportBool(values[field])
+
+			if err != nil {
+				candidate.Errors = append(candidate.Errors, utils.ErrorMsg{
+					Field:   ImportFields[field],
+					Message: "this field must be of type boolean",
+				})
+
+				continue
+			}
+
+			if field == "done" {
+				input.Done = &value
+			} else {
+				input.Remind = &value
+			}
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
+func parseICSImport(r io.Reader) ([]importCandidate, error) {
+	calendars, err := utils.ParseICS(r)
+
+	if err != nil {
+		return nil, invalidImport("the iCalendar file could not be read: %s", err.Error())
+	}
+
+	var candidates []importCandidate
+
+	for _, calendar := range calendars {
+		if calendar.Name != "VCALENDAR" {
+			continue
+		}
+
+		for _, component := range calendar.Components {
+			if component.Name != "VTODO" && component.Name != "VEVENT" {
+				continue
+			}
+
+			candidate := importCandidate{Row: len(candidates) + 1, Uid: component.Text("UID")}
+			input := &candidate.Input
+			input.Title = importString(component.Text("SUMMARY"))
+			input.Description = importString(component.Text("DESCRIPTION"))
+			input.Priority = importICSPriority(component.Text("PRIORITY"))
+			complexity := "medium"
+			input.Complexity = &complexity
+
+			if labels := utils.ICSCategories(component); len(labels) > 0 {
+				input.Labels = &labels
+			}
+
+			times := map[string]*time.Time{}
+
+			for _, name := range []string{"DTSTART", "DTEND", "DUE", "COMPLETED"} {
+				prop := component.Get(name)
+
+				if prop == nil {
+					continue
+				}
+
+				t, err := utils.ParseICSTime(prop)
+
+				if err != nil {
+					field := "Date"
+
+					if component.Name == "VEVENT" && name == "DTEND" {
+						field = "To"
+					} else if name == "COMPLETED" {
+						field = "Done"
+					}
+
+					candidate.Errors = append(candidate.Errors, utils.ErrorMsg{
+						Field:   field,
+						Message: "this field must be a valid date",
+					})
+
+					continue
+				}
+
+				times[name] = t
+			}
+
+			if component.Name == "VEVENT" {
+				input.Date = times["DTSTART"]
+				start := component.Get("DTSTART")
+
+				if start != nil && start.Params["VALUE"] != "DATE" && times["DTEND"] != nil {
+					input.From = times["DTSTART"]
+					input.To = times["DTEND"]
+				}
+			} else if times["DUE"] != nil {
+				input.Date = times["DUE"]
+			} else {
+				input.Date = times["DTSTART"]
+			}
+
+			done := strings.EqualFold(component.Text("STATUS"), "COMPLETED") || times["COMPLETED"] != nil
+			input.Done = &done
+
+			if done {
+				input.DoneAt = times["COMPLETED"]
+			}
+
+			remind := false
+
+			for _, sub := range component.Components {
+				if sub.Name == "VALARM" {
+					remind = true
+				}
+			}
+
+			input.Remind = &remind
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	return candidates, nil
+}
+
+func importString(value string) *string {
+	if value = strings.TrimSpace(value); value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+func importLevel(value string) *string {
+	if value = strings.ToLower(strings.TrimSpace(value)); value == "" {
+		value = "medium"
+	}
+
+	return &value
+}
+
+func importICSPriority(value string) *string {
+	level := "medium"
+	priority, err := strconv.Atoi(strings.TrimSpace(value))
+
+	if err == nil && priority >= 1 && priority <= 4 {
+		level = "high"
+	} else if err == nil && priority >= 6 && priority <= 9 {
+		level = "low"
+	}
+
+	return &level
+}
+
+func importLabels(value string) []string {
+	var labels []string
+
+	for _, label := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == ',' }) {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+
+	return labels
+}
+
+func parseImportTime(value string) (*time.Time, error) {
+	for _, layout := range importTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid date '%s'", value)
+}
+
+func parseImportBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return false, nil
+	case "yes", "y", "x":
+		return true, nil
+	case "no", "n":
+		return false, nil
+	}
+
+	return strconv.ParseBool(value)
+}
+
+func removeImportFile(imp models.Import) {
+	if imp.FilePath == nil {
+		return
+	}
+
+	if err := os.Remove(*imp.FilePath); err != nil && !os.IsNotExist(err) {
+		log.Printf("Could not remove import file '%s': %s", *imp.FilePath, err.Error())
+	}
+}
+
+func importExpiration() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("IMPORT_EXPIRATION_HOURS"))
+
+	if err != nil || hours < 1 {
+		hours = 24
+	}
+
+	return time.Hour * time.Duration(hours)
+}
+
+func expireImports(ctx context.Context, db *mongo.Database) error {
+	coll := db.Collection("imports")
+
+	filter := bson.D{
+		{Key: "status", Value: "completed"},
+		{Key: "dry_run", Value: true},
+		{Key: "updated_at", Value: bson.D{{Key: "$lte", Value: time.Now().Add(-importExpiration())}}},
+	}
+
+	var imports []models.Import
+
+	if err := findAll(ctx, coll, filter, bson.D{{Key: "file_path", Value: 1}}, &imports); err != nil {
+		return err
+	}
+
+	for _, imp := range imports {
+		removeImportFile(imp)
+
+		update := bson.D{
+			{
+				Key: "$set",
+				Value: bson.D{
+					{Key: "status", Value: "expired"},
+					{Key: "updated_at", Value: time.Now()},
+				},
+			},
+			{Key: "$unset", Value: bson.D{{Key: "file_path", Value: ""}}},
+		}
+
+		if _, err := coll.UpdateByID(ctx, imp.Id, update); err != nil {
+			return err
+		}
+	}
+
+	if len(imports) > 0 {
+		log.Printf("%d import preview(s) expired", len(imports))
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type job struct {
+	Name string
+	Run  func(ctx context.Context, db *mongo.Database) error
+}
+
+var registry = []job{
+	{Name: "expire unsubscribed accounts", Run: expireUnsubscribedAccounts},
+	{Name: "clean up unverified accounts", Run: cleanUpUnverifiedAccounts},
+	{Name: "process data exports", Run: ProcessDataExports},
+	{Name: "expire data exports", Run: expireDataExports},
+	{Name: "process imports", Run: ProcessImports},
+	{Name: "expire imports", Run: expireImports},
+	{Name: "purge expired data", Run: purgeExpiredData},
+	{Name: "auto archive done tasks", Run: autoArchiveTasks},
+	{Name: "expire sync tombstones", Run: expireTombstones},
+	{Name: "expire webhook deliveries", Run: expireWebhookDeliveries},
+}
+
+func Start(db *mongo.Database) {
+	ListenForWebhooks(db)
+
+	if os.Getenv("JOBS_ENABLED") == "false" {
+		log.Println("Background jobs disabled")
+		return
+	}
+
+	minutes, err := strconv.Atoi(os.Getenv("JOBS_INTERVAL_MINUTES"))
+
+	if err != nil || minutes < 1 {
+		minutes = 60
+	}
+
+	startWebhookDeliveries(db)
+
+	go func() {
+		ticker := time.NewTicker(time.Minute * time.Duration(minutes))
+		defer ticker.Stop()
+
+		for {
+			runAll(db)
+			<-ticker.C
+		}
+	}()
+}
+
+func runAll(db *mongo.Database) {
+	for _, j := range registry {
+		if err := j.Run(context.TODO(), db); err != nil {
+			log.Printf("Job '%s' failed: %s", j.Name, err.Error())
+		}
+	}
+}
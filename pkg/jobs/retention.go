@@ -0,0 +1,323 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/history"
+	"github.com/Bryan-an/tasker-backend/pkg/common/lifecycle"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/timetracking"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	PurgeKindUser  = "user"
+	PurgeKindTasks = "deleted_tasks"
+)
+
+func DeletedTasksRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("RETENTION_DELETED_TASKS_DAYS"))
+
+	if err != nil {
+		days = 30
+	}
+
+	return time.Hour * 24 * time.Duration(days)
+}
+
+func PendingDeletionRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("RETENTION_PENDING_DELETION_DAYS"))
+
+	if err != nil {
+		days = 7
+	}
+
+	return time.Hour * 24 * time.Duration(days)
+}
+
+func PurgeData(ctx context.Context, db *mongo.Database, dryRun bool) ([]models.PurgeAudit, error) {
+	audits := []models.PurgeAudit{}
+	usersCollection := db.Collection("users")
+
+	graceCutoff := bson.D{{Key: "$lte", Value: time.Now().Add(-PendingDeletionRetention())}}
+
+	usersFilter := bson.D{
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "status", Value: lifecycle.StatusDeleted}},
+			bson.D{
+				{Key: "status", Value: lifecycle.StatusPendingDeletion},
+				{Key: "status_changed_at", Value: graceCutoff},
+			},
+			bson.D{
+				{Key: "status", Value: lifecycle.StatusPendingDeletion},
+				{Key: "status_changed_at", Value: nil},
+				{Key: "updated_at", Value: graceCutoff},
+			},
+		}},
+	}
+
+	var users []models.User
+
+	if err := findAll(ctx, usersCollection, usersFilter, bson.D{{Key: "_id", Value: 1}, {Key: "email", Value: 1}}, &users); err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		deleted, err := purgeUser(ctx, db, user, dryRun)
+
+		if err != nil {
+			return audits, err
+		}
+
+		audit, err := recordPurge(ctx, db, PurgeKindUser, user.Id, deleted, dryRun)
+
+		if err != nil {
+			return audits, err
+		}
+
+		audits = append(audits, audit)
+	}
+
+	cutoff := bson.D{{Key: "$lte", Value: time.Now().Add(-DeletedTasksRetention())}}
+
+	tasksFilter := bson.D{
+		{Key: "status", Value: "deleted"},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "deleted_at", Value: cutoff}},
+			bson.D{
+				{Key: "deleted_at", Value: nil},
+				{Key: "updated_at", Value: cutoff},
+			},
+		}},
+	}
+
+	count, historyCount, timeCount, err := purgeDeletedTasks(ctx, db, tasksFilter, dryRun)
+
+	if err != nil {
+		return audits, err
+	}
+
+	if count > 0 {
+		deleted := map[string]int64{"tasks": count, history.Collection: historyCount, timetracking.Collection: timeCount}
+		audit, err := recordPurge(ctx, db, PurgeKindTasks, nil, deleted, dryRun)
+
+		if err != nil {
+			return audits, err
+		}
+
+		audits = append(audits, audit)
+	}
+
+	return audits, nil
+}
+
+func purgeUser(ctx context.Context, db *mongo.Database, user models.User, dryRun bool) (map[string]int64, error) {
+	deleted := map[string]int64{}
+	ownedFilter := bson.D{{Key: "user_id", Value: user.Id}}
+
+	var exports []models.Export
+
+	if err := findAll(ctx, db.Collection("exports"), ownedFilter, nil, &exports); err != nil {
+		return nil, err
+	}
+
+	for _, export := range exports {
+		if export.FilePath == nil {
+			continue
+		}
+
+		deleted["files"]++
+
+		if dryRun {
+			continue
+		}
+
+		if err := os.Remove(*export.FilePath); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	var imports []models.Import
+
+	if err := findAll(ctx, db.Collection("imports"), ownedFilter, nil, &imports); err != nil {
+		return nil, err
+	}
+
+	for _, imp := range imports {
+		if imp.FilePath == nil {
+			continue
+		}
+
+		deleted["files"]++
+
+		if dryRun {
+			continue
+		}
+
+		if err := os.Remove(*imp.FilePath); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	for _, name := range []string{"tasks", history.Collection, history.TombstonesCollection, "settings", "access_tokens", "exports", "webhooks", "webhook_deliveries", "calendar_feeds", "imports", "labels", "saved_filters", timetracking.Collection, "stream_tickets"} {
+		count, err := deleteOrCount(ctx, db.Collection(name), ownedFilter, dryRun)
+
+		if err != nil {
+			return nil, err
+		}
+
+		deleted[name] = count
+	}
+
+	emailFilter := bson.D{{Key: "email", Value: user.Email}}
+
+	for _, name := range []string{"verifications", "login_links"} {
+		count, err := deleteOrCount(ctx, db.Collection(name), emailFilter, dryRun)
+
+		if err != nil {
+			return nil, err
+		}
+
+		deleted[name] = count
+	}
+
+	count, err := deleteOrCount(ctx, db.Collection("users"), bson.D{{Key: "_id", Value: user.Id}}, dryRun)
+
+	if err != nil {
+		return nil, err
+	}
+
+	deleted["users"] = count
+
+	return deleted, nil
+}
+
+func purgeDeletedTasks(ctx context.Context, db *mongo.Database, filter bson.D, dryRun bool) (int64, int64, int64, error) {
+	var tasks []models.Task
+
+	projection := bson.D{{Key: "_id", Value: 1}, {Key: "user_id", Value: 1}}
+
+	if err := findAll(ctx, db.Collection("tasks"), filter, projection, &tasks); err != nil {
+		return 0, 0, 0, err
+	}
+
+	if len(tasks) == 0 {
+		return 0, 0, 0, nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(tasks))
+	tombstones := make([]models.Tombstone, 0, len(tasks))
+
+	for _, task := range tasks {
+		ids = append(ids, *task.Id)
+		tombstones = append(tombstones, models.Tombstone{UserId: task.UserId, TaskId: task.Id})
+	}
+
+	idsFilter := bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}}
+	count, err := deleteOrCount(ctx, db.Collection("tasks"), append(idsFilter, filter...), dryRun)
+
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	taskIdsFilter := bson.D{{Key: "task_id", Value: bson.D{{Key: "$in", Value: ids}}}}
+	historyCount, err := deleteOrCount(ctx, db.Collection(history.Collection), taskIdsFilter, dryRun)
+
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	timeCount, err := deleteOrCount(ctx, db.Collection(timetracking.Collection), taskIdsFilter, dryRun)
+
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	if !dryRun {
+		if err := history.RecordTombstones(ctx, db, tombstones); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+
+	return count, historyCount, timeCount, nil
+}
+
+func deleteOrCount(ctx context.Context, coll *mongo.Collection, filter bson.D, dryRun bool) (int64, error) {
+	if dryRun {
+		return coll.CountDocuments(ctx, filter)
+	}
+
+	result, err := coll.DeleteMany(ctx, filter)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
+func recordPurge(ctx context.Context, db *mongo.Database, kind string, userId *primitive.ObjectID, deleted map[string]int64, dryRun bool) (models.PurgeAudit, error) {
+	now := time.Now()
+
+	audit := models.PurgeAudit{
+		Kind:      &kind,
+		UserId:    userId,
+		DryRun:    &dryRun,
+		Deleted:   deleted,
+		CreatedAt: &now,
+	}
+
+	if dryRun {
+		return audit, nil
+	}
+
+	result, err := db.Collection("purge_audits").InsertOne(ctx, audit)
+
+	if err != nil {
+		return audit, err
+	}
+
+	id := result.InsertedID.(primitive.ObjectID)
+	audit.Id = &id
+
+	return audit, nil
+}
+
+func purgeExpiredData(ctx context.Context, db *mongo.Database) error {
+	dryRun := os.Getenv("RETENTION_DRY_RUN") == "true"
+	audits, err := PurgeData(ctx, db, dryRun)
+
+	for _, audit := range audits {
+		if dryRun {
+			log.Printf("Retention dry run: would purge %s %v", *audit.Kind, audit.Deleted)
+		} else {
+			log.Printf("Retention: purged %s %v", *audit.Kind, audit.Deleted)
+		}
+	}
+
+	return err
+}
+
+func expireTombstones(ctx context.Context, db *mongo.Database) error {
+	filter := bson.D{
+		{Key: "deleted_at", Value: bson.D{{Key: "$lte", Value: time.Now().Add(-history.TombstoneRetention())}}},
+	}
+
+	result, err := db.Collection(history.TombstonesCollection).DeleteMany(ctx, filter)
+
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount > 0 {
+		log.Printf("%d sync tombstone(s) expired", result.DeletedCount)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/lifecycle"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func expireUnsubscribedAccounts(ctx context.Context, db *mongo.Database) error {
+	cutoff := bson.D{{Key: "$lte", Value: time.Now().Add(-lifecycle.ReactivationGracePeriod())}}
+
+	filter := bson.D{
+		{Key: "status", Value: lifecycle.StatusUnsubscribed},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "status_changed_at", Value: cutoff}},
+			bson.D{
+				{Key: "status_changed_at", Value: nil},
+				{Key: "updated_at", Value: cutoff},
+			},
+		}},
+	}
+
+	result, err := lifecycle.Transition(ctx, db.Collection("users"), filter, lifecycle.StatusPendingDeletion)
+
+	if err != nil {
+		return err
+	}
+
+	if result.ModifiedCount > 0 {
+		log.Printf("%d unsubscribed account(s) scheduled for deletion", result.ModifiedCount)
+	}
+
+	return nil
+}
+
+func cleanUpUnverifiedAccounts(ctx context.Context, db *mongo.Database) error {
+	usersCollection := db.Collection("users")
+
+	filter := bson.D{
+		{Key: "status", Value: lifecycle.StatusCreated},
+		{Key: "created_at", Value: bson.D{
+			{Key: "$lte", Value: time.Now().Add(-lifecycle.UnverifiedAccountTTL())},
+		}},
+	}
+
+	opts := options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}, {Key: "email", Value: 1}})
+	cursor, err := usersCollection.Find(ctx, filter, opts)
+
+	if err != nil {
+		return err
+	}
+
+	var users []models.User
+
+	if err = cursor.All(ctx, &users); err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		userFilter := bson.D{{Key: "_id", Value: user.Id}}
+		result, err := lifecycle.Transition(ctx, usersCollection, userFilter, lifecycle.StatusDeleted)
+
+		if err != nil {
+			return err
+		}
+
+		if result.ModifiedCount == 0 {
+			continue
+		}
+
+		verificationsFilter := bson.D{{Key: "email", Value: user.Email}}
+
+		if _, err := db.Collection("verifications").DeleteMany(ctx, verificationsFilter); err != nil {
+			return err
+		}
+
+		if err := lifecycle.Anonymize(ctx, usersCollection, *user.Id); err != nil {
+			return err
+		}
+	}
+
+	if len(users) > 0 {
+		log.Printf("%d unverified account(s) removed", len(users))
+	}
+
+	return nil
+}
@@ -0,0 +1,436 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/events"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var webhookClient = utils.NewPublicHTTPClient(10 * time.Second)
+
+type webhookPayload struct {
+	Id        string       `json:"id"`
+	Type      string       `json:"type"`
+	CreatedAt time.Time    `json:"created_at"`
+	Data      interface{}  `json:"data"`
+	Task      *models.Task `json:"task,omitempty"`
+}
+
+func ListenForWebhooks(db *mongo.Database) {
+	events.Listen(func(userId primitive.ObjectID, event events.Event) {
+		if err := enqueueWebhooks(context.TODO(), db, userId, event); err != nil {
+			log.Printf("Error queueing webhooks for event '%s': %s", event.Type, err.Error())
+		}
+	})
+}
+
+func webhookEventTypes(event events.Event) []string {
+	change, ok := event.Data.(events.TaskChange)
+
+	if !ok {
+		return []string{event.Type}
+	}
+
+	types := []string{event.Type}
+
+	switch change.Action {
+	case models.TaskActionReplaced, models.TaskActionReverted:
+		types = []string{events.TaskEvent(models.TaskActionUpdated)}
+	}
+
+	if change.Action == models.TaskActionCreated {
+		return types
+	}
+
+	for _, fieldChange := range change.Changes {
+		if fieldChange.Field == "done" && isTrue(fieldChange.New) {
+			types = append(types, "task.completed")
+		}
+	}
+
+	return types
+}
+
+func enqueueWebhooks(ctx context.Context, db *mongo.Database, userId primitive.ObjectID, event events.Event) error {
+	types := webhookEventTypes(event)
+
+	filter := bson.D{
+		{Key: "user_id", Value: userId},
+		{Key: "active", Value: true},
+		{Key: "events", Value: bson.D{{Key: "$in", Value: types}}},
+	}
+
+	var webhooks []models.Webhook
+
+	if err := findAll(ctx, db.Collection("webhooks"), filter, bson.D{{Key: "events", Value: 1}}, &webhooks); err != nil {
+		return err
+	}
+
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	var task *models.Task
+
+	if change, ok := event.Data.(events.TaskChange); ok {
+		var t models.Task
+		err := db.Collection("tasks").FindOne(ctx, bson.D{{Key: "_id", Value: change.TaskId}}).Decode(&t)
+
+		if err != nil && err != mongo.ErrNoDocuments {
+			return err
+		}
+
+		if err == nil {
+			task = &t
+		}
+	}
+
+	now := time.Now()
+	status := models.DeliveryPending
+	attempts := 0
+	deliveries := []interface{}{}
+
+	for _, webhook := range webhooks {
+		for _, eventType := range types {
+			if !contains(*webhook.Events, eventType) {
+				continue
+			}
+
+			data, err := json.Marshal(webhookPayload{
+				Id:        event.Id,
+				Type:      eventType,
+				CreatedAt: event.CreatedAt,
+				Data:      event.Data,
+				Task:      task,
+			})
+
+			if err != nil {
+				return err
+			}
+
+			payload := string(data)
+			eventType := eventType
+
+			deliveries = append(deliveries, models.WebhookDelivery{
+				WebhookId:     webhook.Id,
+				UserId:        &userId,
+				Event:         &eventType,
+				Payload:       &payload,
+				Status:        &status,
+				Attempts:      &attempts,
+				NextAttemptAt: &now,
+				CreatedAt:     &now,
+				UpdatedAt:     &now,
+			})
+		}
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	_, err := db.Collection("webhook_deliveries").InsertMany(ctx, deliveries)
+	return err
+}
+
+func startWebhookDeliveries(db *mongo.Database) {
+	seconds, err := strconv.Atoi(os.Getenv("WEBHOOK_POLL_SECONDS"))
+
+	if err != nil || seconds < 1 {
+		seconds = 5
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Second * time.Duration(seconds))
+		defer ticker.Stop()
+
+		for {
+			if err := deliverWebhooks(context.TODO(), db); err != nil {
+				log.Printf("Webhook delivery failed: %s", err.Error())
+			}
+
+			<-ticker.C
+		}
+	}()
+}
+
+func deliverWebhooks(ctx context.Context, db *mongo.Database) error {
+	deliveriesCollection := db.Collection("webhook_deliveries")
+
+	for {
+		now := time.Now()
+
+		filter := bson.D{
+			{Key: "$or", Value: bson.A{
+				bson.D{
+					{Key: "status", Value: models.DeliveryPending},
+					{Key: "next_attempt_at", Value: bson.D{{Key: "$lte", Value: now}}},
+				},
+				bson.D{
+					{Key: "status", Value: models.DeliverySending},
+					{Key: "last_attempt_at", Value: bson.D{{Key: "$lte", Value: now.Add(-5 * time.Minute)}}},
+				},
+			}},
+		}
+
+		update := bson.D{
+			{
+				Key: "$set",
+				Value: bson.D{
+					{Key: "status", Value: models.DeliverySending},
+					{Key: "last_attempt_at", Value: now},
+					{Key: "updated_at", Value: now},
+				},
+			},
+			{Key: "$inc", Value: bson.D{{Key: "attempts", Value: 1}}},
+		}
+
+		opts := options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+			SetReturnDocument(options.After)
+
+		var delivery models.WebhookDelivery
+
+		if err := deliveriesCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil
+			}
+
+			return err
+		}
+
+		if err := deliverWebhook(ctx, db, delivery); err != nil {
+			return err
+		}
+	}
+}
+
+func deliverWebhook(ctx context.Context, db *mongo.Database, delivery models.WebhookDelivery) error {
+	deliveriesCollection := db.Collection("webhook_deliveries")
+	var webhook models.Webhook
+
+	err := db.Collection("webhooks").FindOne(ctx, bson.D{{Key: "_id", Value: delivery.WebhookId}}).Decode(&webhook)
+
+	if err != nil && err != mongo.ErrNoDocuments {
+		return err
+	}
+
+	if err == mongo.ErrNoDocuments || webhook.Active == nil || !*webhook.Active {
+		update := bson.D{{Key: "$set", Value: bson.D{
+			{Key: "status", Value: models.DeliveryFailed},
+			{Key: "error", Value: "webhook is disabled"},
+			{Key: "updated_at", Value: time.Now()},
+		}}}
+
+		_, err := deliveriesCollection.UpdateByID(ctx, delivery.Id, update)
+		return err
+	}
+
+	code, body, sendErr := sendWebhook(webhook, delivery)
+	now := time.Now()
+	result := bson.D{{Key: "updated_at", Value: now}}
+
+	if code != 0 {
+		result = append(result, bson.E{Key: "response_code", Value: code})
+	}
+
+	if sendErr == nil {
+		result = append(result, bson.E{Key: "status", Value: models.DeliverySucceeded}, bson.E{Key: "response_body", Value: body})
+		update := bson.D{{Key: "$set", Value: result}, {Key: "$unset", Value: bson.D{{Key: "error", Value: ""}}}}
+
+		if _, err := deliveriesCollection.UpdateByID(ctx, delivery.Id, update); err != nil {
+			return err
+		}
+
+		reset := bson.D{{Key: "$set", Value: bson.D{{Key: "consecutive_failures", Value: 0}}}}
+		_, err := db.Collection("webhooks").UpdateByID(ctx, webhook.Id, reset)
+
+		return err
+	}
+
+	result = append(result, bson.E{Key: "error", Value: sendErr.Error()})
+
+	if *delivery.Attempts >= webhookMaxAttempts() {
+		result = append(result, bson.E{Key: "status", Value: models.DeliveryFailed})
+	} else {
+		result = append(result,
+			bson.E{Key: "status", Value: models.DeliveryPending},
+			bson.E{Key: "next_attempt_at", Value: now.Add(webhookBackoff(*delivery.Attempts))},
+		)
+	}
+
+	if _, err := deliveriesCollection.UpdateByID(ctx, delivery.Id, bson.D{{Key: "$set", Value: result}}); err != nil {
+		return err
+	}
+
+	return registerWebhookFailure(ctx, db, webhook)
+}
+
+func sendWebhook(webhook models.Webhook, delivery models.WebhookDelivery) (int, string, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(*webhook.Secret))
+	mac.Write([]byte(timestamp + "." + *delivery.Payload))
+
+	req, err := http.NewRequest(http.MethodPost, *webhook.URL, bytes.NewBufferString(*delivery.Payload))
+
+	if err != nil {
+		return 0, "", err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Tasker-Webhooks/1.0")
+	req.Header.Set("X-Tasker-Event", *delivery.Event)
+	req.Header.Set("X-Tasker-Delivery", delivery.Id.Hex())
+	req.Header.Set("X-Tasker-Timestamp", timestamp)
+	req.Header.Set("X-Tasker-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	res, err := webhookClient.Do(req)
+
+	if err != nil {
+		return 0, "", err
+	}
+
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, string(body), fmt.Errorf("endpoint responded with status %d", res.StatusCode)
+	}
+
+	return res.StatusCode, string(body), nil
+}
+
+func registerWebhookFailure(ctx context.Context, db *mongo.Database, webhook models.Webhook) error {
+	webhooksCollection := db.Collection("webhooks")
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "consecutive_failures", Value: 1}}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated models.Webhook
+
+	if err := webhooksCollection.FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: webhook.Id}}, update, opts).Decode(&updated); err != nil {
+		return err
+	}
+
+	if updated.ConsecutiveFailures == nil || *updated.ConsecutiveFailures < webhookDisableAfter() {
+		return nil
+	}
+
+	now := time.Now()
+
+	filter := bson.D{
+		{Key: "_id", Value: webhook.Id},
+		{Key: "active", Value: true},
+	}
+
+	disable := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "active", Value: false},
+		{Key: "disabled_at", Value: now},
+		{Key: "updated_at", Value: now},
+	}}}
+
+	result, err := webhooksCollection.UpdateOne(ctx, filter, disable)
+
+	if err == nil && result.ModifiedCount > 0 {
+		log.Printf("Webhook %s disabled after %d consecutive failures", webhook.Id.Hex(), *updated.ConsecutiveFailures)
+	}
+
+	return err
+}
+
+func expireWebhookDeliveries(ctx context.Context, db *mongo.Database) error {
+	days, err := strconv.Atoi(os.Getenv("WEBHOOK_DELIVERY_RETENTION_DAYS"))
+
+	if err != nil {
+		days = 30
+	}
+
+	filter := bson.D{
+		{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{models.DeliverySucceeded, models.DeliveryFailed}}}},
+		{Key: "created_at", Value: bson.D{{Key: "$lte", Value: time.Now().AddDate(0, 0, -days)}}},
+	}
+
+	result, err := db.Collection("webhook_deliveries").DeleteMany(ctx, filter)
+
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount > 0 {
+		log.Printf("%d webhook delivery log(s) expired", result.DeletedCount)
+	}
+
+	return nil
+}
+
+func webhookMaxAttempts() int {
+	attempts, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+
+	if err != nil || attempts < 1 {
+		attempts = 6
+	}
+
+	return attempts
+}
+
+func webhookDisableAfter() int {
+	failures, err := strconv.Atoi(os.Getenv("WEBHOOK_DISABLE_AFTER_FAILURES"))
+
+	if err != nil || failures < 1 {
+		failures = 20
+	}
+
+	return failures
+}
+
+func webhookBackoff(attempts int) time.Duration {
+	backoff := 30 * time.Second
+
+	for i := 1; i < attempts && backoff < 6*time.Hour; i++ {
+		backoff *= 2
+	}
+
+	if backoff > 6*time.Hour {
+		backoff = 6 * time.Hour
+	}
+
+	return backoff
+}
+
+func isTrue(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case *bool:
+		return v != nil && *v
+	default:
+		return false
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
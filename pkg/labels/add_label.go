@@ -0,0 +1,101 @@
+package labels
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/labels"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type addInput struct {
+	Name      *string `json:"name" binding:"required,max=50"`
+	Color     *string `json:"color" binding:"omitempty,hexcolor"`
+	SortOrder *int    `json:"sort_order" binding:"omitempty,min=0"`
+}
+
+func (h handler) AddLabel(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var input addInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	name := strings.TrimSpace(*input.Name)
+
+	if out := validateName(name); out != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		return
+	}
+
+	key := labels.Key(name)
+	sortOrder := 0
+	now := time.Now()
+
+	if input.SortOrder != nil {
+		sortOrder = *input.SortOrder
+	}
+
+	label := models.Label{
+		UserId:    uid,
+		Name:      &name,
+		Key:       &key,
+		Color:     input.Color,
+		SortOrder: &sortOrder,
+		CreatedAt: &now,
+		UpdatedAt: &now,
+	}
+
+	req, err := h.DB.Collection(labels.Collection).InsertOne(context.TODO(), label)
+
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "a label with this name already exists"})
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "label created successfully",
+		"id":      req.InsertedID,
+	})
+}
+
+func validateName(name string) []utils.ErrorMsg {
+	if name == "" || strings.Contains(name, ",") {
+		return []utils.ErrorMsg{
+			{
+				Field:   "Name",
+				Message: "this field must not be blank or contain commas",
+			},
+		}
+	}
+
+	return nil
+}
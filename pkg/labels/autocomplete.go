@@ -0,0 +1,66 @@
+package labels
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/labels"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (h handler) Autocomplete(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if err != nil || limit < 1 || limit > 50 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": []utils.ErrorMsg{{
+			Field:   "limit",
+			Message: "this query param must be a number between 1 and 50",
+		}}})
+
+		return
+	}
+
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	filter := bson.D{{Key: "user_id", Value: uid}}
+
+	if q := labels.Key(c.Query("q")); q != "" {
+		filter = append(filter, bson.E{Key: "key", Value: primitive.Regex{Pattern: "^" + regexp.QuoteMeta(q)}})
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "sort_order", Value: 1}, {Key: "key", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := h.DB.Collection(labels.Collection).Find(context.TODO(), filter, opts)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var result []models.Label
+
+	if err = cursor.All(context.TODO(), &result); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if result == nil {
+		result = []models.Label{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
@@ -0,0 +1,39 @@
+package labels
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/labels"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (h handler) DeleteLabel(c *gin.Context) {
+	label := h.findLabel(c)
+
+	if label == nil {
+		return
+	}
+
+	count, err := h.relabel(c, *label.UserId, []string{*label.Name}, "", func(ctx mongo.SessionContext) error {
+		_, err := h.DB.Collection(labels.Collection).DeleteOne(ctx, bson.D{{Key: "_id", Value: label.Id}})
+		return err
+	})
+
+	if err != nil {
+		if errors.Is(err, errRelabelConflict) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": errRelabelConflict.Error()})
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "label deleted successfully",
+		"tasks_updated": count,
+	})
+}
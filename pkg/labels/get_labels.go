@@ -0,0 +1,175 @@
+package labels
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/labels"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type labelUsage struct {
+	Key   string `bson:"_id"`
+	Name  string `bson:"name"`
+	Count int64  `bson:"count"`
+}
+
+func (h handler) GetLabels(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	usage, err := h.labelUsage(*uid)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	names := make([]string, 0, len(usage))
+
+	for _, u := range usage {
+		names = append(names, u.Name)
+	}
+
+	if err := labels.Ensure(context.TODO(), h.DB, *uid, names); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	filter := bson.D{{Key: "user_id", Value: uid}}
+	opts := options.Find().SetSort(bson.D{{Key: "sort_order", Value: 1}, {Key: "key", Value: 1}})
+	cursor, err := h.DB.Collection(labels.Collection).Find(context.TODO(), filter, opts)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var result []models.Label
+
+	if err = cursor.All(context.TODO(), &result); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if result == nil {
+		result = []models.Label{}
+	}
+
+	for i := range result {
+		var count int64
+
+		if u, ok := usage[*result[i].Key]; ok {
+			count = u.Count
+		}
+
+		result[i].UsageCount = &count
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+func (h handler) GetLabel(c *gin.Context) {
+	label := h.findLabel(c)
+
+	if label == nil {
+		return
+	}
+
+	usage, err := h.labelUsage(*label.UserId)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	count := usage[*label.Key].Count
+	label.UsageCount = &count
+
+	c.JSON(http.StatusOK, gin.H{"data": label})
+}
+
+func (h handler) labelUsage(uid primitive.ObjectID) (map[string]labelUsage, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "user_id", Value: uid},
+			{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{"created", "archived"}}}},
+		}}},
+		{{Key: "$unwind", Value: "$labels"}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$toLower", Value: bson.D{{Key: "$trim", Value: bson.D{{Key: "input", Value: "$labels"}}}}}}},
+			{Key: "name", Value: bson.D{{Key: "$first", Value: "$labels"}}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+	}
+
+	cursor, err := h.DB.Collection("tasks").Aggregate(context.TODO(), pipeline)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []labelUsage
+
+	if err = cursor.All(context.TODO(), &rows); err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]labelUsage, len(rows))
+
+	for _, row := range rows {
+		usage[row.Key] = row
+	}
+
+	return usage, nil
+}
+
+func (h handler) findLabel(c *gin.Context) *models.Label {
+	labelId := c.Param("id")
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return nil
+	}
+
+	id, err := primitive.ObjectIDFromHex(labelId)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return nil
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "user_id", Value: uid},
+	}
+
+	var label models.Label
+
+	if err := h.DB.Collection(labels.Collection).FindOne(context.TODO(), filter).Decode(&label); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("label not found with id '%s'", labelId),
+			})
+
+			return nil
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return nil
+	}
+
+	return &label
+}
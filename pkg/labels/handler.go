@@ -0,0 +1,31 @@
+package labels
+
+import (
+	"github.com/Bryan-an/tasker-backend/pkg/common/middlewares"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type handler struct {
+	DB     *mongo.Database
+	Client *mongo.Client
+}
+
+func RegisterRoutes(r *gin.Engine, db *mongo.Database, client *mongo.Client) {
+	h := &handler{
+		DB:     db,
+		Client: client,
+	}
+
+	routes := r.Group("/api/v1/labels")
+
+	routes.Use(middlewares.JwtAuthMiddleware(db))
+	routes.Use(middlewares.RequireScopes("tasks:read", "tasks:write"))
+	routes.GET("/", h.GetLabels)
+	routes.GET("/autocomplete", h.Autocomplete)
+	routes.POST("/", h.AddLabel)
+	routes.POST("/merge", h.MergeLabels)
+	routes.GET("/:id", h.GetLabel)
+	routes.PATCH("/:id", h.UpdateLabel)
+	routes.DELETE("/:id", h.DeleteLabel)
+}
@@ -0,0 +1,146 @@
+package labels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/labels"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mergeInput struct {
+	SourceIds *[]string `json:"source_ids" binding:"required,min=1"`
+	TargetId  *string   `json:"target_id" binding:"required"`
+}
+
+func (h handler) MergeLabels(c *gin.Context) {
+	var input mergeInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	ids := []primitive.ObjectID{}
+	labelIds := append([]string{*input.TargetId}, *input.SourceIds...)
+
+	for _, labelId := range labelIds {
+		id, err := primitive.ObjectIDFromHex(labelId)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("label not found with id '%s'", labelId),
+			})
+
+			return
+		}
+
+		ids = append(ids, id)
+	}
+
+	for _, id := range ids[1:] {
+		if id == ids[0] {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": []utils.ErrorMsg{{
+				Field:   "SourceIds",
+				Message: "this field must not contain the target label",
+			}}})
+
+			return
+		}
+	}
+
+	labelsCollection := h.DB.Collection(labels.Collection)
+
+	filter := bson.D{
+		{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}},
+		{Key: "user_id", Value: uid},
+	}
+
+	cursor, err := labelsCollection.Find(context.TODO(), filter)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var found []models.Label
+
+	if err = cursor.All(context.TODO(), &found); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	byId := map[primitive.ObjectID]models.Label{}
+
+	for _, label := range found {
+		byId[*label.Id] = label
+	}
+
+	names := []string{}
+
+	for i, id := range ids {
+		label, ok := byId[id]
+
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("label not found with id '%s'", labelIds[i]),
+			})
+
+			return
+		}
+
+		if i > 0 {
+			names = append(names, *label.Name)
+		}
+	}
+
+	target := byId[ids[0]]
+
+	count, err := h.relabel(c, *uid, names, *target.Name, func(ctx mongo.SessionContext) error {
+		sources := bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$in", Value: ids[1:]}}},
+			{Key: "user_id", Value: uid},
+		}
+
+		_, err := labelsCollection.DeleteMany(ctx, sources)
+		return err
+	})
+
+	if err != nil {
+		if errors.Is(err, errRelabelConflict) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": errRelabelConflict.Error()})
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "labels merged successfully",
+		"tasks_updated": count,
+	})
+}
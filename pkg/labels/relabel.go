@@ -0,0 +1,168 @@
+package labels
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/events"
+	"github.com/Bryan-an/tasker-backend/pkg/common/history"
+	"github.com/Bryan-an/tasker-backend/pkg/common/labels"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+var errRelabelConflict = errors.New("some tasks were modified while updating their labels, try again")
+
+func (h handler) relabel(c *gin.Context, uid primitive.ObjectID, from []string, to string, fn func(ctx mongo.SessionContext) error) (int, error) {
+	wc := writeconcern.Majority()
+	txnOptions := options.Transaction().SetWriteConcern(wc)
+	session, err := h.Client.StartSession()
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer session.EndSession(context.TODO())
+
+	source := c.Request.Method + " " + c.FullPath()
+	var entries []models.TaskHistory
+
+	_, err = session.WithTransaction(context.TODO(), func(ctx mongo.SessionContext) (interface{}, error) {
+		if err := fn(ctx); err != nil {
+			return nil, err
+		}
+
+		entries, err = h.relabelTasks(ctx, uid, from, to, source)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, history.RecordMany(ctx, h.DB, entries)
+	}, txnOptions)
+
+	if err != nil {
+		return 0, err
+	}
+
+	for _, entry := range entries {
+		events.Publish(uid, events.TaskEvent(*entry.Action), events.TaskChange{
+			TaskId:  *entry.TaskId,
+			Action:  *entry.Action,
+			Changes: entry.Changes,
+		})
+	}
+
+	return len(entries), nil
+}
+
+func (h handler) relabelTasks(ctx mongo.SessionContext, uid primitive.ObjectID, from []string, to string, source string) ([]models.TaskHistory, error) {
+	tasksCollection := h.DB.Collection("tasks")
+	keys := map[string]bool{}
+	patterns := bson.A{}
+
+	for _, name := range from {
+		key := labels.Key(name)
+		keys[key] = true
+		patterns = append(patterns, primitive.Regex{Pattern: "^\\s*" + regexp.QuoteMeta(key) + "\\s*$", Options: "i"})
+	}
+
+	filter := bson.D{
+		{Key: "user_id", Value: uid},
+		{Key: "labels", Value: bson.D{{Key: "$in", Value: patterns}}},
+	}
+
+	opts := options.Find().SetProjection(bson.D{{Key: "labels", Value: 1}, {Key: "version", Value: 1}})
+	cursor, err := tasksCollection.Find(ctx, filter, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []models.Task
+
+	if err = cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	action := models.TaskActionUpdated
+	entries := make([]models.TaskHistory, 0, len(tasks))
+
+	for _, task := range tasks {
+		old := *task.Labels
+		updated := []string{}
+		seen := map[string]bool{}
+
+		for _, label := range old {
+			if keys[labels.Key(label)] {
+				if to == "" {
+					continue
+				}
+
+				label = to
+			}
+
+			if seen[labels.Key(label)] {
+				continue
+			}
+
+			seen[labels.Key(label)] = true
+			updated = append(updated, label)
+		}
+
+		var version int64
+
+		if task.Version != nil {
+			version = *task.Version
+		}
+
+		taskFilter := bson.D{
+			{Key: "_id", Value: task.Id},
+			utils.VersionFilter(version),
+		}
+
+		update := bson.D{
+			{
+				Key: "$set",
+				Value: bson.D{
+					{Key: "labels", Value: updated},
+					{Key: "updated_at", Value: now},
+				},
+			},
+			{
+				Key:   "$inc",
+				Value: bson.D{{Key: "version", Value: 1}},
+			},
+		}
+
+		result, err := tasksCollection.UpdateOne(ctx, taskFilter, update)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if result.MatchedCount == 0 {
+			return nil, errRelabelConflict
+		}
+
+		entries = append(entries, models.TaskHistory{
+			TaskId:  task.Id,
+			UserId:  &uid,
+			ActorId: &uid,
+			Action:  &action,
+			Source:  &source,
+			Changes: []models.FieldChange{{Field: "labels", Old: old, New: updated}},
+		})
+	}
+
+	return entries, nil
+}
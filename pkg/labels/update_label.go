@@ -0,0 +1,110 @@
+package labels
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/labels"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type updateInput struct {
+	Name      *string `json:"name" binding:"omitempty,max=50"`
+	Color     *string `json:"color" binding:"omitempty,hexcolor"`
+	SortOrder *int    `json:"sort_order" binding:"omitempty,min=0"`
+}
+
+func (h handler) UpdateLabel(c *gin.Context) {
+	var input updateInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	label := h.findLabel(c)
+
+	if label == nil {
+		return
+	}
+
+	data := bson.D{{Key: "updated_at", Value: time.Now()}}
+
+	if input.Color != nil {
+		data = append(data, bson.E{Key: "color", Value: input.Color})
+	}
+
+	if input.SortOrder != nil {
+		data = append(data, bson.E{Key: "sort_order", Value: input.SortOrder})
+	}
+
+	rename := input.Name != nil && strings.TrimSpace(*input.Name) != *label.Name
+	var name string
+
+	if rename {
+		name = strings.TrimSpace(*input.Name)
+
+		if out := validateName(name); out != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+			return
+		}
+
+		data = append(data, bson.E{Key: "name", Value: name}, bson.E{Key: "key", Value: labels.Key(name)})
+	}
+
+	update := bson.D{{Key: "$set", Value: data}}
+	labelsCollection := h.DB.Collection(labels.Collection)
+
+	if !rename {
+		if _, err := labelsCollection.UpdateByID(context.TODO(), label.Id, update); err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "label updated successfully"})
+		return
+	}
+
+	count, err := h.relabel(c, *label.UserId, []string{*label.Name}, name, func(ctx mongo.SessionContext) error {
+		_, err := labelsCollection.UpdateByID(ctx, label.Id, update)
+		return err
+	})
+
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"error": "a label with this name already exists, merge the labels instead",
+			})
+
+			return
+		}
+
+		if errors.Is(err, errRelabelConflict) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": errRelabelConflict.Error()})
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "label renamed successfully",
+		"tasks_updated": count,
+	})
+}
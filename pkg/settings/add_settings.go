@@ -6,10 +6,12 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Bryan-an/tasker-backend/pkg/common/events"
 	"github.com/Bryan-an/tasker-backend/pkg/common/models"
 	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type notification struct {
@@ -18,8 +20,10 @@ type notification struct {
 }
 
 type addInput struct {
-	Notifications *notification `json:"notifications" binding:"required"`
-	Theme         *string       `json:"theme" binding:"required,oneof=dark light"`
+	Notifications   *notification `json:"notifications" binding:"required"`
+	Theme           *string       `json:"theme" binding:"required,oneof=dark light"`
+	AutoArchiveDays *int          `json:"auto_archive_days" binding:"omitempty,min=0"`
+	Timezone        *string       `json:"timezone" binding:"omitempty,timezone"`
 }
 
 func (h handler) AddSettings(c *gin.Context) {
@@ -46,6 +50,7 @@ func (h handler) AddSettings(c *gin.Context) {
 	}
 
 	now := time.Now()
+	version := int64(1)
 
 	s := models.Settings{
 		UserId: uid,
@@ -53,9 +58,12 @@ func (h handler) AddSettings(c *gin.Context) {
 			Email:  input.Notifications.Email,
 			Mobile: input.Notifications.Mobile,
 		},
-		Theme:     input.Theme,
-		CreatedAt: &now,
-		UpdatedAt: &now,
+		Theme:           input.Theme,
+		AutoArchiveDays: input.AutoArchiveDays,
+		Timezone:        input.Timezone,
+		Version:         &version,
+		CreatedAt:       &now,
+		UpdatedAt:       &now,
 	}
 
 	settingsCollection := h.DB.Collection("settings")
@@ -66,6 +74,10 @@ func (h handler) AddSettings(c *gin.Context) {
 		return
 	}
 
+	id := req.InsertedID.(primitive.ObjectID)
+	s.Id = &id
+	events.Publish(*uid, events.SettingsUpdated, s)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "settings added successfully",
 		"id":      req.InsertedID,
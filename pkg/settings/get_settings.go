@@ -38,5 +38,11 @@ func (h handler) GetSettings(c *gin.Context) {
 		return
 	}
 
+	if utils.IfNoneMatch(c, settings.Version) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	utils.SetETag(c, settings.Version)
 	c.JSON(http.StatusOK, gin.H{"data": settings})
 }
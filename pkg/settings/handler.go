@@ -19,7 +19,8 @@ func RegisterRoutes(r *gin.Engine, db *mongo.Database, client *mongo.Client) {
 
 	routes := r.Group("/api/v1/settings")
 
-	routes.Use(middlewares.JwtAuthMiddleware())
+	routes.Use(middlewares.JwtAuthMiddleware(db))
+	routes.Use(middlewares.RequireScopes("settings:read", "settings:write"))
 	routes.GET("/", h.GetSettings)
 	routes.POST("/", h.AddSettings)
 	routes.PUT("/", h.ReplaceSettings)
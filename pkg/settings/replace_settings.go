@@ -7,15 +7,21 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Bryan-an/tasker-backend/pkg/common/events"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
 	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type replaceInput struct {
-	Notifications *notification `json:"notifications" binding:"required"`
-	Theme         *string       `json:"theme" binding:"required,oneof=dark light"`
+	Notifications   *notification `json:"notifications" binding:"required"`
+	Theme           *string       `json:"theme" binding:"required,oneof=dark light"`
+	AutoArchiveDays *int          `json:"auto_archive_days" binding:"omitempty,min=0"`
+	Timezone        *string       `json:"timezone" binding:"omitempty,timezone"`
 }
 
 func (h handler) ReplaceSettings(c *gin.Context) {
@@ -41,6 +47,13 @@ func (h handler) ReplaceSettings(c *gin.Context) {
 		return
 	}
 
+	ifMatch, err := utils.IfMatch(c)
+
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	settingsCollection := h.DB.Collection("settings")
 	filter := bson.D{{Key: "user_id", Value: uid}}
 
@@ -50,19 +63,47 @@ func (h handler) ReplaceSettings(c *gin.Context) {
 			Value: bson.D{
 				{Key: "notifications", Value: input.Notifications},
 				{Key: "theme", Value: input.Theme},
+				{Key: "auto_archive_days", Value: input.AutoArchiveDays},
+				{Key: "timezone", Value: input.Timezone},
 				{Key: "updated_at", Value: time.Now()},
 			},
 		},
+		{
+			Key:   "$inc",
+			Value: bson.D{{Key: "version", Value: 1}},
+		},
 	}
 
-	result, err := settingsCollection.UpdateOne(context.TODO(), filter, update)
-
-	if err != nil {
-		c.AbortWithError(http.StatusInternalServerError, err)
-		return
+	if ifMatch != nil {
+		filter = append(filter, utils.VersionFilter(*ifMatch))
 	}
 
-	if result.MatchedCount == 0 {
+	var settings models.Settings
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	if err := settingsCollection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&settings); err != nil {
+		if err != mongo.ErrNoDocuments {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		if ifMatch != nil {
+			count, err := settingsCollection.CountDocuments(context.TODO(), bson.D{{Key: "user_id", Value: uid}})
+
+			if err != nil {
+				c.AbortWithError(http.StatusInternalServerError, err)
+				return
+			}
+
+			if count > 0 {
+				c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{
+					"error": "settings were modified by another request, fetch them again and retry",
+				})
+
+				return
+			}
+		}
+
 		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
 			"error": fmt.Sprintf(
 				"settings not found for user with id '%s'",
@@ -73,6 +114,9 @@ func (h handler) ReplaceSettings(c *gin.Context) {
 		return
 	}
 
+	utils.SetETag(c, settings.Version)
+	events.Publish(*uid, events.SettingsUpdated, settings)
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "settings replaced successfully",
 	})
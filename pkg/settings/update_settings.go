@@ -8,10 +8,15 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Bryan-an/tasker-backend/pkg/common/events"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/timezone"
 	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type JSONNotifications struct {
@@ -26,8 +31,10 @@ type notifications struct {
 }
 
 type UpdateInput struct {
-	Notifications JSONNotifications `json:"notifications"`
-	Theme         utils.JSONString  `json:"theme"`
+	Notifications   JSONNotifications `json:"notifications"`
+	Theme           utils.JSONString  `json:"theme"`
+	AutoArchiveDays utils.JSONInt     `json:"auto_archive_days"`
+	Timezone        utils.JSONString  `json:"timezone"`
 }
 
 func (n *JSONNotifications) UnmarshalJSON(data []byte) error {
@@ -72,6 +79,13 @@ func (h handler) UpdateSettings(c *gin.Context) {
 		return
 	}
 
+	ifMatch, err := utils.IfMatch(c)
+
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	settingsCollection := h.DB.Collection("settings")
 	filter := bson.D{{Key: "user_id", Value: uid}}
 
@@ -109,21 +123,85 @@ func (h handler) UpdateSettings(c *gin.Context) {
 		}
 	}
 
+	if input.AutoArchiveDays.Set {
+		if input.AutoArchiveDays.Valid {
+			if input.AutoArchiveDays.Value < 0 {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": []utils.ErrorMsg{
+					{
+						Field:   "AutoArchiveDays",
+						Message: "this field must be greater than or equal to 0",
+					},
+				}})
+
+				return
+			}
+
+			data["auto_archive_days"] = input.AutoArchiveDays.Value
+		} else {
+			data["auto_archive_days"] = nil
+		}
+	}
+
+	if input.Timezone.Set {
+		if input.Timezone.Valid {
+			if _, err := timezone.Load(input.Timezone.Value); err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": []utils.ErrorMsg{
+					{
+						Field:   "Timezone",
+						Message: "this field must be a valid IANA time zone",
+					},
+				}})
+
+				return
+			}
+
+			data["timezone"] = input.Timezone.Value
+		} else {
+			data["timezone"] = nil
+		}
+	}
+
 	update := bson.D{
 		{
 			Key:   "$set",
 			Value: data,
 		},
+		{
+			Key:   "$inc",
+			Value: bson.D{{Key: "version", Value: 1}},
+		},
 	}
 
-	result, err := settingsCollection.UpdateOne(context.TODO(), filter, update)
-
-	if err != nil {
-		c.AbortWithError(http.StatusInternalServerError, err)
-		return
+	if ifMatch != nil {
+		filter = append(filter, utils.VersionFilter(*ifMatch))
 	}
 
-	if result.MatchedCount == 0 {
+	var settings models.Settings
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	if err := settingsCollection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&settings); err != nil {
+		if err != mongo.ErrNoDocuments {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		if ifMatch != nil {
+			count, err := settingsCollection.CountDocuments(context.TODO(), bson.D{{Key: "user_id", Value: uid}})
+
+			if err != nil {
+				c.AbortWithError(http.StatusInternalServerError, err)
+				return
+			}
+
+			if count > 0 {
+				c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{
+					"error": "settings were modified by another request, fetch them again and retry",
+				})
+
+				return
+			}
+		}
+
 		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
 			"error": fmt.Sprintf(
 				"settings not found for user with id '%s'",
@@ -134,6 +212,9 @@ func (h handler) UpdateSettings(c *gin.Context) {
 		return
 	}
 
+	utils.SetETag(c, settings.Version)
+	events.Publish(*uid, events.SettingsUpdated, settings)
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "settings updated successfully",
 	})
@@ -0,0 +1,151 @@
+package stats
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const maxLabels = 20
+
+type countRow struct {
+	Key   string `bson:"_id"`
+	Count int64  `bson:"count"`
+}
+
+type rateRow struct {
+	Key   *string `bson:"_id"`
+	Total int64   `bson:"total"`
+	Done  int64   `bson:"done"`
+}
+
+type durationRow struct {
+	Average float64 `bson:"average"`
+	Count   int64   `bson:"count"`
+}
+
+type labelRow struct {
+	Name  string `bson:"name"`
+	Count int64  `bson:"count"`
+}
+
+type statsResult struct {
+	Created    []countRow    `bson:"created"`
+	Completed  []countRow    `bson:"completed"`
+	Priority   []rateRow     `bson:"priority"`
+	Complexity []rateRow     `bson:"complexity"`
+	Duration   []durationRow `bson:"duration"`
+	Overdue    []countRow    `bson:"overdue"`
+	Labels     []labelRow    `bson:"labels"`
+	DoneDays   []countRow    `bson:"done_days"`
+}
+
+func (h handler) aggregate(uid primitive.ObjectID, from time.Time, end time.Time, today time.Time, loc *time.Location, format string) (*statsResult, error) {
+	tz := loc.String()
+	inRange := bson.D{{Key: "$gte", Value: from}, {Key: "$lt", Value: end}}
+	createdInRange := bson.D{{Key: "$match", Value: bson.D{{Key: "created_at", Value: inRange}}}}
+
+	completedInRange := bson.D{{Key: "$match", Value: bson.D{
+		{Key: "done", Value: true},
+		{Key: "done_at", Value: inRange},
+	}}}
+
+	byPeriod := func(field string) bson.D {
+		return bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$dateToString", Value: bson.D{
+				{Key: "format", Value: format},
+				{Key: "date", Value: "$" + field},
+				{Key: "timezone", Value: tz},
+			}}}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}}
+	}
+
+	rateBy := func(field string) bson.D {
+		return bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$" + field},
+			{Key: "total", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "done", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{
+				bson.D{{Key: "$eq", Value: bson.A{"$done", true}}}, 1, 0,
+			}}}}}},
+		}}}
+	}
+
+	facets := bson.D{
+		{Key: "created", Value: bson.A{createdInRange, byPeriod("created_at")}},
+		{Key: "completed", Value: bson.A{completedInRange, byPeriod("done_at")}},
+		{Key: "priority", Value: bson.A{createdInRange, rateBy("priority")}},
+		{Key: "complexity", Value: bson.A{createdInRange, rateBy("complexity")}},
+		{Key: "duration", Value: bson.A{
+			completedInRange,
+			bson.D{{Key: "$match", Value: bson.D{{Key: "created_at", Value: bson.D{{Key: "$ne", Value: nil}}}}}},
+			bson.D{{Key: "$group", Value: bson.D{
+				{Key: "_id", Value: nil},
+				{Key: "average", Value: bson.D{{Key: "$avg", Value: bson.D{{Key: "$subtract", Value: bson.A{"$done_at", "$created_at"}}}}}},
+				{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+			}}},
+		}},
+		{Key: "overdue", Value: bson.A{
+			bson.D{{Key: "$match", Value: bson.D{
+				{Key: "status", Value: "created"},
+				{Key: "done", Value: bson.D{{Key: "$ne", Value: true}}},
+				{Key: "date", Value: bson.D{{Key: "$lt", Value: today}}},
+			}}},
+			bson.D{{Key: "$count", Value: "count"}},
+		}},
+		{Key: "labels", Value: bson.A{
+			createdInRange,
+			bson.D{{Key: "$unwind", Value: "$labels"}},
+			bson.D{{Key: "$group", Value: bson.D{
+				{Key: "_id", Value: bson.D{{Key: "$toLower", Value: bson.D{{Key: "$trim", Value: bson.D{{Key: "input", Value: "$labels"}}}}}}},
+				{Key: "name", Value: bson.D{{Key: "$first", Value: "$labels"}}},
+				{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+			}}},
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+			bson.D{{Key: "$limit", Value: maxLabels}},
+		}},
+		{Key: "done_days", Value: bson.A{
+			bson.D{{Key: "$match", Value: bson.D{
+				{Key: "done", Value: true},
+				{Key: "done_at", Value: bson.D{{Key: "$lt", Value: today.AddDate(0, 0, 1)}}},
+			}}},
+			bson.D{{Key: "$group", Value: bson.D{
+				{Key: "_id", Value: bson.D{{Key: "$dateToString", Value: bson.D{
+					{Key: "format", Value: "%Y-%m-%d"},
+					{Key: "date", Value: "$done_at"},
+					{Key: "timezone", Value: tz},
+				}}}},
+				{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+			}}},
+		}},
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "user_id", Value: uid},
+			{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{"created", "archived"}}}},
+		}}},
+		{{Key: "$facet", Value: facets}},
+	}
+
+	cursor, err := h.DB.Collection("tasks").Aggregate(context.TODO(), pipeline)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var results []statsResult
+
+	if err = cursor.All(context.TODO(), &results); err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return &statsResult{}, nil
+	}
+
+	return &results[0], nil
+}
@@ -0,0 +1,316 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/timezone"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	dateLayout   = "2006-01-02"
+	maxRangeDays = 366
+)
+
+var levels = []string{"low", "medium", "high"}
+
+var periodFormats = map[string]string{
+	"day":  "%Y-%m-%d",
+	"week": "%G-W%V",
+}
+
+type activity struct {
+	Period    string `json:"period"`
+	Created   int64  `json:"created"`
+	Completed int64  `json:"completed"`
+}
+
+type completionRate struct {
+	Value string  `json:"value"`
+	Total int64   `json:"total"`
+	Done  int64   `json:"done"`
+	Rate  float64 `json:"rate"`
+}
+
+type labelCount struct {
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+}
+
+type streak struct {
+	Current int `json:"current"`
+	Longest int `json:"longest"`
+}
+
+func (h handler) GetStats(c *gin.Context) {
+	interval := c.DefaultQuery("interval", "day")
+	queryParamsErrors := []utils.ErrorMsg{}
+
+	if _, ok := periodFormats[interval]; !ok {
+		queryParamsErrors = append(queryParamsErrors, utils.ErrorMsg{
+			Field:   "interval",
+			Message: "this query param must be one of the following values: day week",
+		})
+	}
+
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	loc, err := timezone.ForUser(context.TODO(), h.DB, uid, c.Query("tz"))
+
+	if err == timezone.ErrInvalid {
+		queryParamsErrors = append(queryParamsErrors, utils.ErrorMsg{
+			Field:   "tz",
+			Message: "this query param must be a valid IANA time zone",
+		})
+
+		loc = time.UTC
+	} else if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	to := today
+	from := today.AddDate(0, 0, -29)
+
+	if param := c.Query("to"); param != "" {
+		if to, err = time.ParseInLocation(dateLayout, param, loc); err != nil {
+			queryParamsErrors = append(queryParamsErrors, utils.ErrorMsg{
+				Field:   "to",
+				Message: "this query param must be a date in the format YYYY-MM-DD",
+			})
+		} else if c.Query("from") == "" {
+			from = to.AddDate(0, 0, -29)
+		}
+	}
+
+	if param := c.Query("from"); param != "" {
+		if from, err = time.ParseInLocation(dateLayout, param, loc); err != nil {
+			queryParamsErrors = append(queryParamsErrors, utils.ErrorMsg{
+				Field:   "from",
+				Message: "this query param must be a date in the format YYYY-MM-DD",
+			})
+		}
+	}
+
+	if len(queryParamsErrors) == 0 {
+		if to.Before(from) {
+			queryParamsErrors = append(queryParamsErrors, utils.ErrorMsg{
+				Field:   "to",
+				Message: "this query param must not be earlier than from",
+			})
+		} else if to.Sub(from) > time.Hour*24*maxRangeDays {
+			queryParamsErrors = append(queryParamsErrors, utils.ErrorMsg{
+				Field:   "to",
+				Message: fmt.Sprintf("the date range must not exceed %d days", maxRangeDays),
+			})
+		}
+	}
+
+	if len(queryParamsErrors) > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": queryParamsErrors})
+		return
+	}
+
+	end := to.AddDate(0, 0, 1)
+	result, err := h.aggregate(*uid, from, end, today, loc, periodFormats[interval])
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	created := countsByKey(result.Created)
+	completed := countsByKey(result.Completed)
+	periods := []activity{}
+	var totalCreated, totalCompleted int64
+
+	for _, period := range periodKeys(from, to, interval) {
+		periods = append(periods, activity{
+			Period:    period,
+			Created:   created[period],
+			Completed: completed[period],
+		})
+
+		totalCreated += created[period]
+		totalCompleted += completed[period]
+	}
+
+	var averageHours *float64
+
+	if len(result.Duration) > 0 && result.Duration[0].Count > 0 {
+		hours := round(result.Duration[0].Average/float64(time.Hour/time.Millisecond), 2)
+		averageHours = &hours
+	}
+
+	var overdue int64
+
+	if len(result.Overdue) > 0 {
+		overdue = result.Overdue[0].Count
+	}
+
+	labels := make([]labelCount, len(result.Labels))
+
+	for i, label := range result.Labels {
+		labels[i] = labelCount{Label: label.Name, Count: label.Count}
+	}
+
+	days := make([]string, len(result.DoneDays))
+
+	for i, day := range result.DoneDays {
+		days[i] = day.Key
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"from":     from.Format(dateLayout),
+		"to":       to.Format(dateLayout),
+		"timezone": loc.String(),
+		"interval": interval,
+		"activity": periods,
+		"totals": gin.H{
+			"created":   totalCreated,
+			"completed": totalCompleted,
+		},
+		"completion_rate": gin.H{
+			"priority":   completionRates(result.Priority),
+			"complexity": completionRates(result.Complexity),
+		},
+		"average_completion_hours": averageHours,
+		"overdue":                  overdue,
+		"labels":                   labels,
+		"streak":                   streaks(days, today),
+	}})
+}
+
+func periodKeys(from time.Time, to time.Time, interval string) []string {
+	keys := []string{}
+	seen := map[string]bool{}
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		key := day.Format(dateLayout)
+
+		if interval == "week" {
+			year, week := day.ISOWeek()
+			key = fmt.Sprintf("%d-W%02d", year, week)
+		}
+
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func countsByKey(rows []countRow) map[string]int64 {
+	counts := make(map[string]int64, len(rows))
+
+	for _, row := range rows {
+		counts[row.Key] = row.Count
+	}
+
+	return counts
+}
+
+func completionRates(rows []rateRow) []completionRate {
+	byValue := make(map[string]rateRow, len(rows))
+
+	for _, row := range rows {
+		key := "none"
+
+		if row.Key != nil {
+			key = *row.Key
+		}
+
+		existing := byValue[key]
+		existing.Total += row.Total
+		existing.Done += row.Done
+		byValue[key] = existing
+	}
+
+	values := append([]string{}, levels...)
+
+	if _, ok := byValue["none"]; ok {
+		values = append(values, "none")
+	}
+
+	rates := make([]completionRate, 0, len(values))
+
+	for _, value := range values {
+		row := byValue[value]
+		rate := completionRate{Value: value, Total: row.Total, Done: row.Done}
+
+		if row.Total > 0 {
+			rate.Rate = round(float64(row.Done)/float64(row.Total), 4)
+		}
+
+		rates = append(rates, rate)
+	}
+
+	return rates
+}
+
+func streaks(days []string, today time.Time) streak {
+	sort.Strings(days)
+
+	var result streak
+	var run int
+	var previous time.Time
+
+	for i, key := range days {
+		day, err := time.Parse(dateLayout, key)
+
+		if err != nil {
+			continue
+		}
+
+		if i > 0 && day.Equal(previous.AddDate(0, 0, 1)) {
+			run++
+		} else {
+			run = 1
+		}
+
+		if run > result.Longest {
+			result.Longest = run
+		}
+
+		previous = day
+	}
+
+	completed := make(map[string]bool, len(days))
+
+	for _, key := range days {
+		completed[key] = true
+	}
+
+	day := today
+
+	if !completed[day.Format(dateLayout)] {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	for completed[day.Format(dateLayout)] {
+		result.Current++
+		day = day.AddDate(0, 0, -1)
+	}
+
+	return result
+}
+
+func round(value float64, places int) float64 {
+	factor := math.Pow(10, float64(places))
+	return math.Round(value*factor) / factor
+}
@@ -6,10 +6,13 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Bryan-an/tasker-backend/pkg/common/history"
 	"github.com/Bryan-an/tasker-backend/pkg/common/models"
 	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type addInput struct {
@@ -48,10 +51,47 @@ func (h handler) AddTask(c *gin.Context) {
 		return
 	}
 
+	t := newTask(uid, input)
+	tasksCollection := h.DB.Collection("tasks")
+	req, err := tasksCollection.InsertOne(context.TODO(), t)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	id := req.InsertedID.(primitive.ObjectID)
+	fields, err := history.ToMap(t)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	undoToken, err := h.recordHistory(c, id, uid, models.TaskActionCreated, history.Diff(bson.M{}, fields))
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, withUndoToken(gin.H{
+		"message": "task added successfully",
+		"id":      req.InsertedID,
+	}, undoToken))
+}
+
+func newTask(uid *primitive.ObjectID, input addInput) models.Task {
 	status := "created"
 	now := time.Now()
+	version := int64(1)
+	var doneAt *time.Time
 
-	t := models.Task{
+	if *input.Done {
+		doneAt = &now
+	}
+
+	return models.Task{
 		UserId:      uid,
 		Title:       input.Title,
 		Description: input.Description,
@@ -62,22 +102,11 @@ func (h handler) AddTask(c *gin.Context) {
 		From:        input.From,
 		To:          input.To,
 		Done:        input.Done,
+		DoneAt:      doneAt,
 		Remind:      input.Remind,
 		Status:      &status,
+		Version:     &version,
 		CreatedAt:   &now,
 		UpdatedAt:   &now,
 	}
-
-	tasksCollection := h.DB.Collection("tasks")
-	req, err := tasksCollection.InsertOne(context.TODO(), t)
-
-	if err != nil {
-		c.AbortWithError(http.StatusInternalServerError, err)
-		return
-	}
-
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "task added successfully",
-		"id":      req.InsertedID,
-	})
 }
@@ -0,0 +1,158 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (h handler) ArchiveTask(c *gin.Context) {
+	taskId := c.Param("id")
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(taskId)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	tasksCollection := h.DB.Collection("tasks")
+
+	filter := bson.D{
+		{Key: "user_id", Value: uid},
+		{Key: "_id", Value: id},
+		{Key: "status", Value: "created"},
+	}
+
+	now := time.Now()
+
+	update := bson.D{
+		{
+			Key: "$set",
+			Value: bson.D{
+				{Key: "status", Value: "archived"},
+				{Key: "archived_at", Value: now},
+				{Key: "updated_at", Value: now},
+			},
+		},
+		{
+			Key:   "$inc",
+			Value: bson.D{{Key: "version", Value: 1}},
+		},
+	}
+
+	result, err := tasksCollection.UpdateOne(context.TODO(), filter, update)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("task not found with id '%s'", taskId),
+		})
+
+		return
+	}
+
+	changes := statusChange("created", "archived", models.FieldChange{Field: "archived_at", New: now})
+	undoToken, err := h.recordHistory(c, id, uid, models.TaskActionArchived, changes)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, withUndoToken(gin.H{
+		"message": "task archived successfully",
+	}, undoToken))
+}
+
+func (h handler) UnarchiveTask(c *gin.Context) {
+	taskId := c.Param("id")
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(taskId)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	tasksCollection := h.DB.Collection("tasks")
+
+	filter := bson.D{
+		{Key: "user_id", Value: uid},
+		{Key: "_id", Value: id},
+		{Key: "status", Value: "archived"},
+	}
+
+	update := bson.D{
+		{
+			Key: "$set",
+			Value: bson.D{
+				{Key: "status", Value: "created"},
+				{Key: "updated_at", Value: time.Now()},
+			},
+		},
+		{
+			Key: "$unset",
+			Value: bson.D{
+				{Key: "archived_at", Value: ""},
+			},
+		},
+		{
+			Key:   "$inc",
+			Value: bson.D{{Key: "version", Value: 1}},
+		},
+	}
+
+	var before bson.M
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+	if err := tasksCollection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&before); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("task not found in archive with id '%s'", taskId),
+			})
+
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	changes := statusChange("archived", "created", models.FieldChange{Field: "archived_at", Old: before["archived_at"]})
+	undoToken, err := h.recordHistory(c, id, uid, models.TaskActionUnarchived, changes)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, withUndoToken(gin.H{
+		"message": "task unarchived successfully",
+	}, undoToken))
+}
@@ -0,0 +1,480 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/history"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/timetracking"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+const (
+	maxBulkTasks = 500
+
+	bulkUpdate      = "update"
+	bulkDelete      = "delete"
+	bulkRestore     = "restore"
+	bulkAddLabel    = "add_label"
+	bulkRemoveLabel = "remove_label"
+
+	bulkApplied  = "applied"
+	bulkNotFound = "not_found"
+	bulkRejected = "rejected"
+)
+
+var bulkActions = map[string]string{
+	bulkUpdate:      models.TaskActionUpdated,
+	bulkDelete:      models.TaskActionDeleted,
+	bulkRestore:     models.TaskActionRestored,
+	bulkAddLabel:    models.TaskActionUpdated,
+	bulkRemoveLabel: models.TaskActionUpdated,
+}
+
+var (
+	errBulkAborted  = errors.New("no changes were applied because some tasks could not be processed")
+	errBulkConflict = errors.New("task was modified by another request")
+)
+
+type bulkInput struct {
+	Action        *string      `json:"action" binding:"required,oneof=update delete restore add_label remove_label"`
+	Ids           []string     `json:"ids" binding:"required_without=Filter"`
+	Filter        *tasksFilter `json:"filter" binding:"required_without=Ids"`
+	Data          *updateInput `json:"data"`
+	Labels        []string     `json:"labels" binding:"omitempty,dive,required"`
+	Transactional bool         `json:"transactional"`
+}
+
+type bulkTarget struct {
+	Id       string
+	ObjectId *primitive.ObjectID
+}
+
+type bulkResult struct {
+	Id     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (h handler) BulkTasks(c *gin.Context) {
+	var input bulkInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	errs := []utils.ErrorMsg{}
+
+	if input.Ids != nil && input.Filter != nil {
+		errs = append(errs, utils.ErrorMsg{Field: "Ids", Message: "this field can't be present together with Filter"})
+	}
+
+	if input.Filter != nil {
+		errs = append(errs, input.Filter.validate()...)
+	}
+
+	if len(input.Ids) > maxBulkTasks {
+		errs = append(errs, utils.ErrorMsg{
+			Field:   "Ids",
+			Message: fmt.Sprintf("this field must contain at most %d element(s)", maxBulkTasks),
+		})
+	}
+
+	if *input.Action == bulkUpdate && input.Data == nil {
+		errs = append(errs, utils.ErrorMsg{Field: "Data", Message: "this field is required when Action is update"})
+	}
+
+	if (*input.Action == bulkAddLabel || *input.Action == bulkRemoveLabel) && len(input.Labels) == 0 {
+		errs = append(errs, utils.ErrorMsg{
+			Field:   "Labels",
+			Message: fmt.Sprintf("this field is required when Action is %s", *input.Action),
+		})
+	}
+
+	if len(errs) > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": errs})
+		return
+	}
+
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	targets, err := h.bulkTargets(uid, input)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if len(targets) > maxBulkTasks {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("the filter matches more than %d tasks, narrow it down and try again", maxBulkTasks),
+		})
+
+		return
+	}
+
+	source := c.Request.Method + " " + c.FullPath()
+	var results []bulkResult
+	var entries []models.TaskHistory
+	var undoToken string
+
+	if input.Transactional {
+		wc := writeconcern.Majority()
+		txnOptions := options.Transaction().SetWriteConcern(wc)
+		session, err := h.Client.StartSession()
+
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		defer session.EndSession(context.TODO())
+
+		_, err = session.WithTransaction(context.TODO(), func(ctx mongo.SessionContext) (interface{}, error) {
+			results, entries, err = h.runBulk(ctx, uid, targets, input, source)
+
+			if err != nil {
+				return nil, err
+			}
+
+			for _, result := range results {
+				if result.Status != bulkApplied {
+					return nil, errBulkAborted
+				}
+			}
+
+			undoToken, err = h.recordBulk(ctx, entries)
+			return nil, err
+		}, txnOptions)
+
+		if err != nil {
+			if errors.Is(err, errBulkAborted) {
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+					"error":   errBulkAborted.Error(),
+					"results": results,
+				})
+
+				return
+			}
+
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+	} else {
+		results, entries, err = h.runBulk(context.TODO(), uid, targets, input, source)
+
+		if err == nil {
+			undoToken, err = h.recordBulk(context.TODO(), entries)
+		}
+
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	for _, entry := range entries {
+		publishTaskEvent(*uid, *entry.TaskId, *entry.Action, entry.Changes)
+
+		if err := h.ensureLabels(*uid, entry.Changes); err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	summary := map[string]int{bulkApplied: 0, bulkNotFound: 0, bulkRejected: 0}
+
+	for _, result := range results {
+		summary[result.Status]++
+	}
+
+	c.JSON(http.StatusOK, withUndoToken(gin.H{
+		"message": "bulk operation completed",
+		"summary": summary,
+		"results": results,
+	}, undoToken))
+}
+
+func (h handler) bulkTargets(uid *primitive.ObjectID, input bulkInput) ([]bulkTarget, error) {
+	var targets []bulkTarget
+
+	if input.Filter == nil {
+		seen := map[string]bool{}
+
+		for _, id := range input.Ids {
+			if seen[id] {
+				continue
+			}
+
+			seen[id] = true
+			target := bulkTarget{Id: id}
+
+			if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+				target.ObjectId = &oid
+			}
+
+			targets = append(targets, target)
+		}
+
+		return targets, nil
+	}
+
+	status := "created"
+
+	if *input.Action == bulkRestore {
+		status = "deleted"
+	}
+
+	opts := options.Find().
+		SetProjection(bson.D{{Key: "_id", Value: 1}}).
+		SetSort(bson.D{{Key: "updated_at", Value: -1}}).
+		SetLimit(maxBulkTasks + 1)
+
+	filter, err := input.Filter.query(uid, status)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := h.DB.Collection("tasks").Find(context.TODO(), filter, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []models.Task
+
+	if err = cursor.All(context.TODO(), &tasks); err != nil {
+		return nil, err
+	}
+
+	for _, task := range tasks {
+		targets = append(targets, bulkTarget{Id: task.Id.Hex(), ObjectId: task.Id})
+	}
+
+	return targets, nil
+}
+
+func (h handler) recordBulk(ctx context.Context, entries []models.TaskHistory) (string, error) {
+	if len(entries) == 0 {
+		return "", nil
+	}
+
+	token, err := history.NewUndoToken(entries)
+
+	if err != nil {
+		return "", err
+	}
+
+	return token, history.RecordMany(ctx, h.DB, entries)
+}
+
+func (h handler) runBulk(ctx context.Context, uid *primitive.ObjectID, targets []bulkTarget, input bulkInput, source string) ([]bulkResult, []models.TaskHistory, error) {
+	results := make([]bulkResult, 0, len(targets))
+	var entries []models.TaskHistory
+
+	for _, target := range targets {
+		result := bulkResult{Id: target.Id}
+
+		if target.ObjectId == nil {
+			result.Status = bulkRejected
+			result.Error = "invalid task id"
+			results = append(results, result)
+			continue
+		}
+
+		changes, err := h.applyBulk(ctx, uid, *target.ObjectId, input)
+
+		switch {
+		case err == mongo.ErrNoDocuments:
+			result.Status = bulkNotFound
+			result.Error = fmt.Sprintf("task not found with id '%s'", target.Id)
+		case errors.Is(err, errBulkConflict):
+			result.Status = bulkRejected
+			result.Error = err.Error()
+		case err != nil:
+			return nil, nil, err
+		default:
+			result.Status = bulkApplied
+		}
+
+		results = append(results, result)
+
+		if result.Status != bulkApplied || len(changes) == 0 {
+			continue
+		}
+
+		action := bulkActions[*input.Action]
+
+		entries = append(entries, models.TaskHistory{
+			TaskId:  target.ObjectId,
+			UserId:  uid,
+			ActorId: uid,
+			Action:  &action,
+			Source:  &source,
+			Changes: changes,
+		})
+	}
+
+	return results, entries, nil
+}
+
+func (h handler) applyBulk(ctx context.Context, uid *primitive.ObjectID, id primitive.ObjectID, input bulkInput) ([]models.FieldChange, error) {
+	tasksCollection := h.DB.Collection("tasks")
+	now := time.Now()
+
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "user_id", Value: uid},
+		{Key: "status", Value: "created"},
+	}
+
+	inc := bson.E{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}}
+	before := bson.M{}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+	switch *input.Action {
+	case bulkUpdate:
+		data := input.Data.data()
+		update := bson.D{{Key: "$set", Value: data}, inc}
+
+		if input.Data.Done.Set {
+			update = withDoneAt(update, input.Data.Done.Valid && input.Data.Done.Value)
+		}
+
+		if err := tasksCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&before); err != nil {
+			return nil, err
+		}
+
+		return history.Diff(before, data), nil
+	case bulkDelete:
+		filter[2] = bson.E{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{"created", "archived"}}}}
+
+		if err := tasksCollection.FindOneAndUpdate(ctx, filter, trashUpdate(now), opts).Decode(&before); err != nil {
+			return nil, err
+		}
+
+		if _, err := timetracking.Stop(ctx, h.DB, *uid, &id); err != nil {
+			return nil, err
+		}
+
+		return trashChanges(before, now), nil
+	case bulkRestore:
+		filter[2] = bson.E{Key: "status", Value: "deleted"}
+
+		if err := tasksCollection.FindOneAndUpdate(ctx, filter, restoreUpdate(now), opts).Decode(&before); err != nil {
+			return nil, err
+		}
+
+		return restoreChanges(before), nil
+	}
+
+	var task models.Task
+
+	if err := tasksCollection.FindOne(ctx, filter).Decode(&task); err != nil {
+		return nil, err
+	}
+
+	var old []string
+
+	if task.Labels != nil {
+		old = *task.Labels
+	}
+
+	labels := bulkLabels(old, input.Labels, *input.Action == bulkAddLabel)
+
+	if len(labels) == len(old) {
+		return nil, nil
+	}
+
+	var version int64
+
+	if task.Version != nil {
+		version = *task.Version
+	}
+
+	update := bson.D{
+		{
+			Key: "$set",
+			Value: bson.D{
+				{Key: "labels", Value: labels},
+				{Key: "updated_at", Value: now},
+			},
+		},
+		inc,
+	}
+
+	result, err := tasksCollection.UpdateOne(ctx, append(filter, utils.VersionFilter(version)), update)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if result.MatchedCount == 0 {
+		return nil, errBulkConflict
+	}
+
+	var oldValue interface{}
+
+	if task.Labels != nil {
+		oldValue = old
+	}
+
+	return []models.FieldChange{{Field: "labels", Old: oldValue, New: labels}}, nil
+}
+
+func bulkLabels(current []string, labels []string, add bool) []string {
+	selected := map[string]bool{}
+
+	for _, label := range labels {
+		selected[label] = true
+	}
+
+	result := []string{}
+
+	for _, label := range current {
+		if add {
+			delete(selected, label)
+		}
+
+		if add || !selected[label] {
+			result = append(result, label)
+		}
+	}
+
+	if add {
+		for _, label := range labels {
+			if selected[label] {
+				result = append(result, label)
+				delete(selected, label)
+			}
+		}
+	}
+
+	return result
+}
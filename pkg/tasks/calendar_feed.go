@@ -0,0 +1,110 @@
+package tasks
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (h handler) GetCalendarFeed(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var feed models.CalendarFeed
+
+	if err := h.DB.Collection("calendar_feeds").FindOne(context.TODO(), bson.D{{Key: "user_id", Value: uid}}).Decode(&feed); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "calendar feed not found"})
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": feed})
+}
+
+func (h handler) RotateCalendarFeed(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	token, err := utils.GetRandomString(32)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	now := time.Now()
+	prefix := token[:8]
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "prefix", Value: prefix},
+			{Key: "token_hash", Value: utils.HashToken(token)},
+			{Key: "updated_at", Value: now},
+		}},
+		{Key: "$unset", Value: bson.D{{Key: "last_used_at", Value: ""}}},
+		{Key: "$setOnInsert", Value: bson.D{{Key: "created_at", Value: now}}},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var feed models.CalendarFeed
+
+	err = h.DB.Collection("calendar_feeds").
+		FindOneAndUpdate(context.TODO(), bson.D{{Key: "user_id", Value: uid}}, update, opts).
+		Decode(&feed)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "calendar feed created successfully",
+		"data":    feed,
+		"token":   token,
+		"url":     strings.TrimSuffix(os.Getenv("API_URL"), "/") + "/api/v1/calendar/" + token + ".ics",
+	})
+}
+
+func (h handler) DeleteCalendarFeed(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	res, err := h.DB.Collection("calendar_feeds").DeleteOne(context.TODO(), bson.D{{Key: "user_id", Value: uid}})
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if res.DeletedCount == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "calendar feed not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "calendar feed deleted successfully"})
+}
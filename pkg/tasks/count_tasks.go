@@ -0,0 +1,49 @@
+package tasks
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+)
+
+func (h handler) CountTasks(c *gin.Context) {
+	f := tasksFilter{
+		Priority:   c.Query("priority"),
+		Complexity: c.Query("complexity"),
+		Labels:     c.Query("labels"),
+		Done:       c.Query("done"),
+		Remind:     c.Query("remind"),
+		Search:     c.Query("search"),
+		Query:      c.Query("query"),
+	}
+
+	if out := f.validate(); len(out) > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		return
+	}
+
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	filter, err := f.query(uid, "created")
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	count, err := h.DB.Collection("tasks").CountDocuments(context.TODO(), filter)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"count": count})
+}
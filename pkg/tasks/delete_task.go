@@ -6,10 +6,16 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Bryan-an/tasker-backend/pkg/common/events"
+	"github.com/Bryan-an/tasker-backend/pkg/common/history"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/timetracking"
 	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func (h handler) DeleteTask(c *gin.Context) {
@@ -30,38 +36,107 @@ func (h handler) DeleteTask(c *gin.Context) {
 
 	tasksCollection := h.DB.Collection("tasks")
 
+	if c.Query("permanent") == "true" {
+		taskFilter := bson.D{
+			{Key: "user_id", Value: uid},
+			{Key: "_id", Value: id},
+		}
+
+		filter := append(taskFilter, bson.E{Key: "status", Value: "deleted"})
+		result, err := tasksCollection.DeleteOne(context.TODO(), filter)
+
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		if result.DeletedCount == 0 {
+			count, err := tasksCollection.CountDocuments(context.TODO(), taskFilter)
+
+			if err != nil {
+				c.AbortWithError(http.StatusInternalServerError, err)
+				return
+			}
+
+			if count > 0 {
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+					"error": "only tasks in the trash can be permanently deleted",
+				})
+
+				return
+			}
+
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("task not found with id '%s'", taskId),
+			})
+
+			return
+		}
+
+		if err := history.DeleteForTasks(context.TODO(), h.DB, []primitive.ObjectID{id}); err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		if err := timetracking.DeleteForTasks(context.TODO(), h.DB, []primitive.ObjectID{id}); err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		tombstones := []models.Tombstone{{UserId: uid, TaskId: &id}}
+
+		if err := history.RecordTombstones(context.TODO(), h.DB, tombstones); err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		events.Publish(*uid, events.TaskPurged, events.TasksPurged{TaskIds: []primitive.ObjectID{id}})
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "task permanently deleted successfully",
+		})
+
+		return
+	}
+
 	filter := bson.D{
 		{Key: "user_id", Value: uid},
 		{Key: "_id", Value: id},
-		{Key: "status", Value: "created"},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{"created", "archived"}}}},
 	}
 
-	update := bson.D{
-		{
-			Key: "$set",
-			Value: bson.D{
-				{Key: "status", Value: "deleted"},
-				{Key: "updated_at", Value: time.Now()},
-			},
-		},
-	}
+	now := time.Now()
+	update := trashUpdate(now)
+	var before bson.M
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
 
-	result, err := tasksCollection.UpdateOne(context.TODO(), filter, update)
+	if err := tasksCollection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&before); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("task not found with id '%s'", taskId),
+			})
+
+			return
+		}
 
-	if err != nil {
 		c.AbortWithError(http.StatusInternalServerError, err)
 		return
 	}
 
-	if result.MatchedCount == 0 {
-		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
-			"error": fmt.Sprintf("task not found with id '%s'", taskId),
-		})
+	if _, err := timetracking.Stop(context.TODO(), h.DB, *uid, &id); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
 
+	changes := trashChanges(before, now)
+	undoToken, err := h.recordHistory(c, id, uid, models.TaskActionDeleted, changes)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	c.JSON(http.StatusOK, withUndoToken(gin.H{
 		"message": "task deleted successfully",
-	})
+	}, undoToken))
 }
@@ -0,0 +1,15 @@
+package tasks
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func withDoneAt(update bson.D, done bool) bson.D {
+	if !done {
+		return append(update, bson.E{Key: "$unset", Value: bson.D{{Key: "done_at", Value: ""}}})
+	}
+
+	return append(update, bson.E{Key: "$min", Value: bson.D{{Key: "done_at", Value: time.Now()}}})
+}
@@ -0,0 +1,87 @@
+package tasks
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/events"
+	"github.com/Bryan-an/tasker-backend/pkg/common/history"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/timetracking"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (h handler) EmptyTrash(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	tasksCollection := h.DB.Collection("tasks")
+
+	filter := bson.D{
+		{Key: "user_id", Value: uid},
+		{Key: "status", Value: "deleted"},
+	}
+
+	opts := options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := tasksCollection.Find(context.TODO(), filter, opts)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var tasks []models.Task
+
+	if err = cursor.All(context.TODO(), &tasks); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(tasks))
+	tombstones := make([]models.Tombstone, 0, len(tasks))
+
+	for _, task := range tasks {
+		ids = append(ids, *task.Id)
+		tombstones = append(tombstones, models.Tombstone{UserId: uid, TaskId: task.Id})
+	}
+
+	filter = append(filter, bson.E{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}})
+	result, err := tasksCollection.DeleteMany(context.TODO(), filter)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := history.DeleteForTasks(context.TODO(), h.DB, ids); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := timetracking.DeleteForTasks(context.TODO(), h.DB, ids); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := history.RecordTombstones(context.TODO(), h.DB, tombstones); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if len(ids) > 0 {
+		events.Publish(*uid, events.TaskPurged, events.TasksPurged{TaskIds: ids})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "trash emptied successfully",
+		"deleted": result.DeletedCount,
+	})
+}
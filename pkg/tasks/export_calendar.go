@@ -0,0 +1,116 @@
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (h handler) ExportCalendar(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	tasks, err := h.findCalendarTasks(c, uid)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=\"tasker-"+time.Now().Format("2006-01-02")+".ics\"")
+	writeCalendar(c, tasks)
+}
+
+func (h handler) findCalendarTasks(c *gin.Context, uid *primitive.ObjectID) ([]models.Task, error) {
+	filter := bson.D{
+		{Key: "user_id", Value: uid},
+		{Key: "status", Value: "created"},
+	}
+
+	if labels := c.Query("labels"); labels != "" {
+		patterns := []string{}
+
+		for _, label := range strings.Split(labels, ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				patterns = append(patterns, "(^"+regexp.QuoteMeta(label)+"$)")
+			}
+		}
+
+		if len(patterns) > 0 {
+			filter = append(filter, bson.E{Key: "labels", Value: bson.D{{
+				Key: "$regex", Value: primitive.Regex{Pattern: strings.Join(patterns, "|"), Options: "i"},
+			}}})
+		}
+	}
+
+	switch c.Query("done") {
+	case "true":
+		filter = append(filter, bson.E{Key: "done", Value: true})
+	case "false":
+		filter = append(filter, bson.E{Key: "done", Value: false})
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := h.DB.Collection("tasks").Find(context.TODO(), filter, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []models.Task
+
+	if err = cursor.All(context.TODO(), &tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func writeCalendar(c *gin.Context, tasks []models.Task) {
+	var body bytes.Buffer
+
+	if err := utils.WriteTasksICS(&body, "Tasker", tasks); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	sum := sha256.Sum256(body.Bytes())
+	etag := "\"" + hex.EncodeToString(sum[:16]) + "\""
+	var lastModified time.Time
+
+	for _, t := range tasks {
+		if t.UpdatedAt != nil && t.UpdatedAt.After(lastModified) {
+			lastModified = *t.UpdatedAt
+		}
+	}
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if utils.MatchesETag(c, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", body.Bytes())
+}
@@ -0,0 +1,158 @@
+package tasks
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/query"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type tasksFilter models.TaskFilter
+
+func (f tasksFilter) query(uid *primitive.ObjectID, status string) (bson.M, error) {
+	filter := bson.M{
+		"user_id": uid,
+		"status":  status,
+	}
+
+	if f.Priority != "" {
+		filter["priority"] = f.Priority
+	}
+
+	if f.Complexity != "" {
+		filter["complexity"] = f.Complexity
+	}
+
+	if f.Labels != "" {
+		ls := strings.Split(f.Labels, ",")
+		patterns := make([]string, len(ls))
+
+		for i, l := range ls {
+			patterns[i] = "(^" + regexp.QuoteMeta(strings.TrimSpace(l)) + "$)"
+		}
+
+		filter["labels"] = bson.D{{
+			Key: "$regex", Value: primitive.Regex{Pattern: strings.Join(patterns, "|"), Options: "i"},
+		}}
+	}
+
+	if f.Done != "" {
+		if f.Done == "true" {
+			filter["done"] = true
+		} else if f.Done == "false" {
+			filter["done"] = false
+		}
+	}
+
+	if f.Remind != "" {
+		if f.Remind == "true" {
+			filter["remind"] = true
+		} else if f.Remind == "false" {
+			filter["remind"] = false
+		}
+	}
+
+	if f.DateFrom != nil || f.DateTo != nil {
+		date := bson.D{}
+
+		if f.DateFrom != nil {
+			date = append(date, bson.E{Key: "$gte", Value: f.DateFrom})
+		}
+
+		if f.DateTo != nil {
+			date = append(date, bson.E{Key: "$lte", Value: f.DateTo})
+		}
+
+		filter["date"] = date
+	}
+
+	if search := strings.TrimSpace(f.Search); search != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(search), Options: "i"}
+
+		filter["$or"] = bson.A{
+			bson.D{{Key: "title", Value: pattern}},
+			bson.D{{Key: "description", Value: pattern}},
+		}
+	}
+
+	if f.Query != "" {
+		expr, err := parseQuery(f.Query)
+
+		if err != nil {
+			return nil, err
+		}
+
+		filter["$and"] = bson.A{expr}
+	}
+
+	return filter, nil
+}
+
+func parseQuery(expr string) (bson.D, error) {
+	node, err := query.Parse(expr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return query.Translate(node, time.Now())
+}
+
+func (f tasksFilter) validate() []utils.ErrorMsg {
+	out := []utils.ErrorMsg{}
+
+	fields := []struct {
+		name    string
+		value   string
+		allowed string
+	}{
+		{"Priority", f.Priority, "low medium high"},
+		{"Complexity", f.Complexity, "low medium high"},
+		{"Done", f.Done, "true false"},
+		{"Remind", f.Remind, "true false"},
+	}
+
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+
+		valid := false
+
+		for _, allowed := range strings.Fields(field.allowed) {
+			if field.value == allowed {
+				valid = true
+			}
+		}
+
+		if !valid {
+			out = append(out, utils.ErrorMsg{
+				Field:   field.name,
+				Message: "this field must be one of the following values: " + field.allowed,
+			})
+		}
+	}
+
+	if f.DateFrom != nil && f.DateTo != nil && f.DateTo.Before(*f.DateFrom) {
+		out = append(out, utils.ErrorMsg{
+			Field:   "DateTo",
+			Message: "this field must be later than DateFrom",
+		})
+	}
+
+	if f.Query != "" {
+		if _, err := parseQuery(f.Query); err != nil {
+			out = append(out, utils.ErrorMsg{
+				Field:   "Query",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	return out
+}
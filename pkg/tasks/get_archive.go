@@ -0,0 +1,82 @@
+package tasks
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (h handler) GetArchive(c *gin.Context) {
+	search := c.Query("q")
+	page, pageSize, queryParamsErrors := utils.GetPagination(c)
+
+	if len(queryParamsErrors) > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": queryParamsErrors})
+		return
+	}
+
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	tasksCollection := h.DB.Collection("tasks")
+	var tasks []models.Task
+
+	filter := bson.M{
+		"user_id": uid,
+		"status":  "archived",
+	}
+
+	if search != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(search), Options: "i"}
+
+		filter["$or"] = bson.A{
+			bson.M{"title": pattern},
+			bson.M{"description": pattern},
+			bson.M{"labels": pattern},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "archived_at", Value: -1}, {Key: "updated_at", Value: -1}}).
+		SetLimit(int64(pageSize)).
+		SetSkip(int64((page - 1) * pageSize))
+
+	cursor, err := tasksCollection.Find(context.TODO(), filter, opts)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if err = cursor.All(context.TODO(), &tasks); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if tasks == nil {
+		tasks = []models.Task{}
+	}
+
+	totalRecords, err := tasksCollection.CountDocuments(context.TODO(), filter)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       tasks,
+		"pagination": utils.GetPaginationInfo(page, pageSize, len(tasks), totalRecords),
+	})
+}
@@ -0,0 +1,64 @@
+package tasks
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/lifecycle"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (h handler) GetCalendar(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	feeds := h.DB.Collection("calendar_feeds")
+	var feed models.CalendarFeed
+
+	if err := feeds.FindOne(context.TODO(), bson.D{{Key: "token_hash", Value: utils.HashToken(token)}}).Decode(&feed); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "calendar feed not found"})
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	userFilter := bson.D{
+		{Key: "_id", Value: feed.UserId},
+		{Key: "status", Value: lifecycle.StatusActive},
+	}
+
+	count, err := h.DB.Collection("users").CountDocuments(context.TODO(), userFilter)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if count == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "calendar feed not found"})
+		return
+	}
+
+	tasks, err := h.findCalendarTasks(c, feed.UserId)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "last_used_at", Value: time.Now()}}}}
+
+	if _, err := feeds.UpdateByID(context.TODO(), feed.Id, update); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	writeCalendar(c, tasks)
+}
@@ -0,0 +1,82 @@
+package tasks
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (h handler) GetFilterTasks(c *gin.Context) {
+	page, pageSize, queryParamsErrors := utils.GetPagination(c)
+
+	if len(queryParamsErrors) > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": queryParamsErrors})
+		return
+	}
+
+	savedFilter := h.findFilter(c)
+
+	if savedFilter == nil {
+		return
+	}
+
+	tasksCollection := h.DB.Collection("tasks")
+	filter, err := savedFilterQuery(savedFilter)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	sort := -1
+
+	if savedFilter.Order != nil && *savedFilter.Order == "asc" {
+		sort = 1
+	}
+
+	sortField := "updated_at"
+
+	if savedFilter.Sort != nil {
+		sortField = *savedFilter.Sort
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: sortField, Value: sort}, {Key: "_id", Value: sort}}).
+		SetLimit(int64(pageSize)).
+		SetSkip(int64((page - 1) * pageSize))
+
+	cursor, err := tasksCollection.Find(context.TODO(), filter, opts)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var tasks []models.Task
+
+	if err = cursor.All(context.TODO(), &tasks); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if tasks == nil {
+		tasks = []models.Task{}
+	}
+
+	totalRecords, err := tasksCollection.CountDocuments(context.TODO(), filter)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       tasks,
+		"pagination": utils.GetPaginationInfo(page, pageSize, len(tasks), totalRecords),
+	})
+}
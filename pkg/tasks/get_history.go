@@ -0,0 +1,128 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/history"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (h handler) GetTaskHistory(c *gin.Context) {
+	taskId := c.Param("id")
+	page, pageSize, queryParamsErrors := utils.GetPagination(c)
+
+	if len(queryParamsErrors) > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": queryParamsErrors})
+		return
+	}
+
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(taskId)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	taskFilter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "user_id", Value: uid},
+	}
+
+	count, err := h.DB.Collection("tasks").CountDocuments(context.TODO(), taskFilter)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if count == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("task not found with id '%s'", taskId),
+		})
+
+		return
+	}
+
+	filter := bson.M{
+		"task_id": id,
+		"user_id": uid,
+	}
+
+	h.findHistory(c, filter, page, pageSize)
+}
+
+func (h handler) GetActivity(c *gin.Context) {
+	page, pageSize, queryParamsErrors := utils.GetPagination(c)
+
+	if len(queryParamsErrors) > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": queryParamsErrors})
+		return
+	}
+
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	filter := bson.M{"user_id": uid}
+
+	if action := c.Query("action"); action != "" {
+		filter["action"] = action
+	}
+
+	h.findHistory(c, filter, page, pageSize)
+}
+
+func (h handler) findHistory(c *gin.Context, filter bson.M, page int, pageSize int) {
+	historyCollection := h.DB.Collection(history.Collection)
+	var entries []models.TaskHistory
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(pageSize)).
+		SetSkip(int64((page - 1) * pageSize))
+
+	cursor, err := historyCollection.Find(context.TODO(), filter, opts)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if err = cursor.All(context.TODO(), &entries); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if entries == nil {
+		entries = []models.TaskHistory{}
+	}
+
+	totalRecords, err := historyCollection.CountDocuments(context.TODO(), filter)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       entries,
+		"pagination": utils.GetPaginationInfo(page, pageSize, len(entries), totalRecords),
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/timetracking"
 	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
@@ -51,5 +52,20 @@ func (h handler) GetTask(c *gin.Context) {
 		return
 	}
 
+	if utils.IfNoneMatch(c, task.Version) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	tasks := []models.Task{task}
+
+	if err := timetracking.Attach(context.TODO(), h.DB, *uid, tasks); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	task = tasks[0]
+
+	utils.SetETag(c, task.Version)
 	c.JSON(http.StatusOK, gin.H{"data": task})
 }
@@ -1,18 +1,16 @@
 package tasks
 
 import (
-	"bytes"
 	"context"
 	"math"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/timetracking"
 	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -22,6 +20,7 @@ func (h handler) GetTasks(c *gin.Context) {
 	labels := c.Query("labels")
 	done := c.Query("done")
 	remind := c.Query("remind")
+	expr := c.Query("query")
 	order := c.DefaultQuery("order", "des")
 	pageParam := c.Query("page")
 	pageSizeParam := c.Query("page_size")
@@ -74,6 +73,15 @@ func (h handler) GetTasks(c *gin.Context) {
 		})
 	}
 
+	if expr != "" {
+		if _, err := parseQuery(expr); err != nil {
+			queryParamsErrors = append(queryParamsErrors, utils.ErrorMsg{
+				Field:   "query",
+				Message: err.Error(),
+			})
+		}
+	}
+
 	if len(queryParamsErrors) > 0 {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": queryParamsErrors})
 		return
@@ -89,51 +97,18 @@ func (h handler) GetTasks(c *gin.Context) {
 	taskCollection := h.DB.Collection("tasks")
 	var tasks []models.Task
 
-	filter := bson.M{
-		"user_id": uid,
-		"status":  "created",
-	}
+	filter, err := tasksFilter{
+		Priority:   priority,
+		Complexity: complexity,
+		Labels:     labels,
+		Done:       done,
+		Remind:     remind,
+		Query:      expr,
+	}.query(uid, "created")
 
-	if priority != "" {
-		filter["priority"] = priority
-	}
-
-	if complexity != "" {
-		filter["complexity"] = complexity
-	}
-
-	if labels != "" {
-		ls := strings.Split(labels, ",")
-
-		var b bytes.Buffer
-
-		for i, l := range ls {
-			if i == 0 {
-				b.WriteString("(^" + l + "$)")
-			} else {
-				b.WriteString("|(^" + l + "$)")
-			}
-		}
-
-		filter["labels"] = bson.D{{
-			Key: "$regex", Value: primitive.Regex{Pattern: b.String(), Options: "i"},
-		}}
-	}
-
-	if done != "" {
-		if done == "true" {
-			filter["done"] = true
-		} else if done == "false" {
-			filter["done"] = false
-		}
-	}
-
-	if remind != "" {
-		if remind == "true" {
-			filter["remind"] = true
-		} else if remind == "false" {
-			filter["remind"] = false
-		}
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
 	}
 
 	var sort int
@@ -165,6 +140,11 @@ func (h handler) GetTasks(c *gin.Context) {
 		tasks = []models.Task{}
 	}
 
+	if err := timetracking.Attach(context.TODO(), h.DB, *uid, tasks); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
 	totalRecords, err := taskCollection.CountDocuments(context.TODO(), filter)
 
 	if err != nil {
@@ -17,14 +17,58 @@ func RegisterRoutes(r *gin.Engine, db *mongo.Database, client *mongo.Client) {
 		Client: client,
 	}
 
+	r.GET("/api/v1/calendar/:token", h.GetCalendar)
+
 	routes := r.Group("/api/v1/tasks")
 
-	routes.Use(middlewares.JwtAuthMiddleware())
+	routes.Use(middlewares.JwtAuthMiddleware(db))
+	routes.Use(middlewares.RequireScopes("tasks:read", "tasks:write"))
 	routes.GET("/", h.GetTasks)
 	routes.GET("/today", h.GetTasksForToday)
+	routes.GET("/count", h.CountTasks)
+	routes.GET("/trash", h.GetTrash)
+	routes.DELETE("/trash", h.EmptyTrash)
+	routes.GET("/archive", h.GetArchive)
+	routes.GET("/activity", h.GetActivity)
+	routes.GET("/export.ics", h.ExportCalendar)
+	routes.POST("/imports", h.ImportTasks)
+	routes.GET("/imports/:id", h.GetImport)
+	routes.POST("/imports/:id/apply", h.ApplyImport)
+	routes.GET("/calendar", h.GetCalendarFeed)
+	routes.POST("/calendar", h.RotateCalendarFeed)
+	routes.DELETE("/calendar", h.DeleteCalendarFeed)
 	routes.POST("/", h.AddTask)
+	routes.POST("/undo", h.UndoTask)
+	routes.POST("/bulk", h.BulkTasks)
 	routes.GET("/:id", h.GetTask)
 	routes.PUT("/:id", h.ReplaceTask)
 	routes.PATCH("/:id", h.UpdateTask)
 	routes.DELETE("/:id", h.DeleteTask)
+	routes.GET("/:id/history", h.GetTaskHistory)
+	routes.POST("/:id/restore", h.RestoreTask)
+	routes.POST("/:id/archive", h.ArchiveTask)
+	routes.POST("/:id/unarchive", h.UnarchiveTask)
+	routes.GET("/:id/time", h.GetTimeEntries)
+	routes.POST("/:id/time", h.AddTimeEntry)
+	routes.DELETE("/:id/time/:entryId", h.DeleteTimeEntry)
+	routes.POST("/:id/timer/start", h.StartTimer)
+	routes.POST("/:id/timer/stop", h.StopTimer)
+
+	syncRoutes := r.Group("/api/v1/sync")
+
+	syncRoutes.Use(middlewares.JwtAuthMiddleware(db))
+	syncRoutes.Use(middlewares.RequireScopes("tasks:read", "tasks:write"))
+	syncRoutes.GET("/", h.GetChanges)
+	syncRoutes.POST("/", h.ApplyMutations)
+
+	filterRoutes := r.Group("/api/v1/filters")
+
+	filterRoutes.Use(middlewares.JwtAuthMiddleware(db))
+	filterRoutes.Use(middlewares.RequireScopes("tasks:read", "tasks:write"))
+	filterRoutes.GET("/", h.GetFilters)
+	filterRoutes.POST("/", h.AddFilter)
+	filterRoutes.GET("/:id", h.GetFilter)
+	filterRoutes.PATCH("/:id", h.UpdateFilter)
+	filterRoutes.DELETE("/:id", h.DeleteFilter)
+	filterRoutes.GET("/:id/tasks", h.GetFilterTasks)
 }
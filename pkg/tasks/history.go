@@ -0,0 +1,66 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/events"
+	"github.com/Bryan-an/tasker-backend/pkg/common/history"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (h handler) recordHistory(c *gin.Context, taskId primitive.ObjectID, uid *primitive.ObjectID, action string, changes []models.FieldChange) (string, error) {
+	if len(changes) == 0 && (action == models.TaskActionUpdated || action == models.TaskActionReplaced) {
+		return "", nil
+	}
+
+	source := c.Request.Method + " " + c.FullPath()
+
+	entries := []models.TaskHistory{{
+		TaskId:  &taskId,
+		UserId:  uid,
+		ActorId: uid,
+		Action:  &action,
+		Source:  &source,
+		Changes: changes,
+	}}
+
+	token, err := history.NewUndoToken(entries)
+
+	if err != nil {
+		return "", err
+	}
+
+	if err := history.Record(context.TODO(), h.DB, entries[0]); err != nil {
+		return "", err
+	}
+
+	if err := h.ensureLabels(*uid, changes); err != nil {
+		return "", err
+	}
+
+	publishTaskEvent(*uid, taskId, action, changes)
+
+	return token, nil
+}
+
+func publishTaskEvent(uid primitive.ObjectID, taskId primitive.ObjectID, action string, changes []models.FieldChange) {
+	events.Publish(uid, events.TaskEvent(action), events.TaskChange{
+		TaskId:  taskId,
+		Action:  action,
+		Changes: changes,
+	})
+}
+
+func statusChange(from string, to string, changes ...models.FieldChange) []models.FieldChange {
+	return append([]models.FieldChange{{Field: "status", Old: from, New: to}}, changes...)
+}
+
+func withUndoToken(body gin.H, token string) gin.H {
+	if token != "" {
+		body["undo_token"] = token
+	}
+
+	return body
+}
@@ -0,0 +1,264 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/Bryan-an/tasker-backend/pkg/jobs"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var importExtensions = map[string]string{
+	".ics":  models.ImportFormatICS,
+	".ical": models.ImportFormatICS,
+	".csv":  models.ImportFormatCSV,
+}
+
+func (h handler) ImportTasks(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	errs := []utils.ErrorMsg{}
+	file, err := c.FormFile("file")
+
+	if err != nil {
+		errs = append(errs, utils.ErrorMsg{Field: "file", Message: "this field is required"})
+	}
+
+	format := strings.ToLower(c.PostForm("format"))
+
+	if format == "" && file != nil {
+		format = importExtensions[strings.ToLower(filepath.Ext(file.Filename))]
+	}
+
+	if format != models.ImportFormatICS && format != models.ImportFormatCSV {
+		errs = append(errs, utils.ErrorMsg{
+			Field:   "format",
+			Message: "this field must be one of the following values: ics csv",
+		})
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultPostForm("dry_run", "false"))
+
+	if err != nil {
+		errs = append(errs, utils.ErrorMsg{Field: "dry_run", Message: "this field must be of type boolean"})
+	}
+
+	var mapping map[string]string
+
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			errs = append(errs, utils.ErrorMsg{Field: "mapping", Message: "this field must be a JSON object of strings"})
+		}
+
+		for field := range mapping {
+			if _, ok := jobs.ImportFields[field]; !ok {
+				errs = append(errs, utils.ErrorMsg{
+					Field:   "mapping",
+					Message: fmt.Sprintf("unknown task field '%s'", field),
+				})
+			}
+		}
+
+		if format == models.ImportFormatICS {
+			errs = append(errs, utils.ErrorMsg{Field: "mapping", Message: "this field is only supported for csv imports"})
+		}
+	}
+
+	if len(errs) > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": errs})
+		return
+	}
+
+	if file.Size > jobs.ImportMaxBytes() {
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error": fmt.Sprintf("the file must not be larger than %d bytes", jobs.ImportMaxBytes()),
+		})
+
+		return
+	}
+
+	if err := os.MkdirAll(jobs.ImportsDir(), 0o700); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	id := primitive.NewObjectID()
+	path := filepath.Join(jobs.ImportsDir(), id.Hex()+"."+format)
+
+	if err := c.SaveUploadedFile(file, path); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	status := "pending"
+	now := time.Now()
+	fileName := filepath.Base(file.Filename)
+
+	imp := models.Import{
+		Id:        &id,
+		UserId:    uid,
+		Format:    &format,
+		FileName:  &fileName,
+		FilePath:  &path,
+		Mapping:   mapping,
+		DryRun:    &dryRun,
+		Status:    &status,
+		CreatedAt: &now,
+		UpdatedAt: &now,
+	}
+
+	if _, err := h.DB.Collection("imports").InsertOne(context.TODO(), imp); err != nil {
+		os.Remove(path)
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	h.processImports()
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "your import is being processed",
+		"id":      id,
+	})
+}
+
+func (h handler) GetImport(c *gin.Context) {
+	importId := c.Param("id")
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(importId)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var imp models.Import
+
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "user_id", Value: uid},
+	}
+
+	if err := h.DB.Collection("imports").FindOne(context.TODO(), filter).Decode(&imp); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("import not found with id '%s'", importId),
+			})
+
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": imp})
+}
+
+func (h handler) ApplyImport(c *gin.Context) {
+	importId := c.Param("id")
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(importId)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	coll := h.DB.Collection("imports")
+
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "user_id", Value: uid},
+		{Key: "dry_run", Value: true},
+		{Key: "status", Value: "completed"},
+	}
+
+	update := bson.D{
+		{
+			Key: "$set",
+			Value: bson.D{
+				{Key: "dry_run", Value: false},
+				{Key: "status", Value: "pending"},
+				{Key: "updated_at", Value: time.Now()},
+			},
+		},
+		{Key: "$unset", Value: bson.D{{Key: "summary", Value: ""}, {Key: "rows", Value: ""}}},
+	}
+
+	var imp models.Import
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	if err := coll.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&imp); err != nil {
+		if err != mongo.ErrNoDocuments {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		count, err := coll.CountDocuments(context.TODO(), bson.D{{Key: "_id", Value: id}, {Key: "user_id", Value: uid}})
+
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		if count == 0 {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("import not found with id '%s'", importId),
+			})
+
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"error": "only completed dry-run imports can be applied",
+		})
+
+		return
+	}
+
+	h.processImports()
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "your import is being processed",
+		"id":      imp.Id,
+	})
+}
+
+func (h handler) processImports() {
+	go func() {
+		if err := jobs.ProcessImports(context.Background(), h.DB); err != nil {
+			log.Println(err.Error())
+		}
+	}()
+}
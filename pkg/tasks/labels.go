@@ -0,0 +1,34 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/labels"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (h handler) ensureLabels(uid primitive.ObjectID, changes []models.FieldChange) error {
+	for _, change := range changes {
+		if change.Field != "labels" {
+			continue
+		}
+
+		var names []string
+
+		switch v := change.New.(type) {
+		case []string:
+			names = v
+		case primitive.A:
+			for _, name := range v {
+				if s, ok := name.(string); ok {
+					names = append(names, s)
+				}
+			}
+		}
+
+		return labels.Ensure(context.TODO(), h.DB, uid, names)
+	}
+
+	return nil
+}
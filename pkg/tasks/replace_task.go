@@ -7,11 +7,15 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Bryan-an/tasker-backend/pkg/common/history"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
 	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type replaceInput struct {
@@ -58,6 +62,13 @@ func (h handler) ReplaceTask(c *gin.Context) {
 		return
 	}
 
+	ifMatch, err := utils.IfMatch(c)
+
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	tasksCollection := h.DB.Collection("tasks")
 
 	filter := bson.D{
@@ -66,41 +77,83 @@ func (h handler) ReplaceTask(c *gin.Context) {
 		{Key: "status", Value: "created"},
 	}
 
+	data := bson.M{
+		"title":       input.Title,
+		"description": input.Description,
+		"labels":      input.Labels,
+		"priority":    input.Priority,
+		"complexity":  input.Complexity,
+		"date":        input.Date,
+		"from":        input.From,
+		"to":          input.To,
+		"done":        input.Done,
+		"remind":      input.Remind,
+		"updated_at":  time.Now(),
+	}
+
 	update := bson.D{
 		{
-			Key: "$set",
-			Value: bson.D{
-				{Key: "title", Value: input.Title},
-				{Key: "description", Value: input.Description},
-				{Key: "labels", Value: input.Labels},
-				{Key: "priority", Value: input.Priority},
-				{Key: "complexity", Value: input.Complexity},
-				{Key: "date", Value: input.Date},
-				{Key: "from", Value: input.From},
-				{Key: "to", Value: input.To},
-				{Key: "done", Value: input.Done},
-				{Key: "remind", Value: input.Remind},
-				{Key: "updated_at", Value: time.Now()},
-			},
+			Key:   "$set",
+			Value: data,
+		},
+		{
+			Key:   "$inc",
+			Value: bson.D{{Key: "version", Value: 1}},
 		},
 	}
 
-	result, err := tasksCollection.UpdateOne(context.TODO(), filter, update)
+	update = withDoneAt(update, *input.Done)
+
+	taskFilter := filter
+
+	if ifMatch != nil {
+		filter = append(filter, utils.VersionFilter(*ifMatch))
+	}
+
+	var before bson.M
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+	if err := tasksCollection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&before); err != nil {
+		if err == mongo.ErrNoDocuments {
+			if ifMatch != nil {
+				count, err := tasksCollection.CountDocuments(context.TODO(), taskFilter)
+
+				if err != nil {
+					c.AbortWithError(http.StatusInternalServerError, err)
+					return
+				}
+
+				if count > 0 {
+					c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{
+						"error": "task was modified by another request, fetch it again and retry",
+					})
+
+					return
+				}
+			}
+
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("task not found with id '%s'", uid),
+			})
+
+			return
+		}
 
-	if err != nil {
 		c.AbortWithError(http.StatusInternalServerError, err)
 		return
 	}
 
-	if result.MatchedCount == 0 {
-		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
-			"error": fmt.Sprintf("task not found with id '%s'", uid),
-		})
+	version := utils.VersionOf(before) + 1
+	utils.SetETag(c, &version)
 
+	undoToken, err := h.recordHistory(c, id, uid, models.TaskActionReplaced, history.Diff(before, data))
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	c.JSON(http.StatusOK, withUndoToken(gin.H{
 		"message": "task replaced successfully",
-	})
+	}, undoToken))
 }
@@ -0,0 +1,70 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (h handler) RestoreTask(c *gin.Context) {
+	taskId := c.Param("id")
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(taskId)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	tasksCollection := h.DB.Collection("tasks")
+
+	filter := bson.D{
+		{Key: "user_id", Value: uid},
+		{Key: "_id", Value: id},
+		{Key: "status", Value: "deleted"},
+	}
+
+	update := restoreUpdate(time.Now())
+	var before bson.M
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+	if err := tasksCollection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&before); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("task not found in trash with id '%s'", taskId),
+			})
+
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	changes := restoreChanges(before)
+	undoToken, err := h.recordHistory(c, id, uid, models.TaskActionRestored, changes)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, withUndoToken(gin.H{
+		"message": "task restored successfully",
+	}, undoToken))
+}
@@ -0,0 +1,309 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type filterInput struct {
+	Name   *string      `json:"name" binding:"required,max=50"`
+	Filter *tasksFilter `json:"filter" binding:"required"`
+	Sort   *string      `json:"sort" binding:"omitempty,oneof=updated_at created_at date title"`
+	Order  *string      `json:"order" binding:"omitempty,oneof=asc desc"`
+	Pinned *bool        `json:"pinned"`
+}
+
+type updateFilterInput struct {
+	Name   *string      `json:"name" binding:"omitempty,max=50"`
+	Filter *tasksFilter `json:"filter"`
+	Sort   *string      `json:"sort" binding:"omitempty,oneof=updated_at created_at date title"`
+	Order  *string      `json:"order" binding:"omitempty,oneof=asc desc"`
+	Pinned *bool        `json:"pinned"`
+}
+
+func (h handler) GetFilters(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	filter := bson.D{{Key: "user_id", Value: uid}}
+
+	if c.Query("pinned") == "true" {
+		filter = append(filter, bson.E{Key: "pinned", Value: true})
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "pinned", Value: -1}, {Key: "created_at", Value: 1}})
+	cursor, err := h.DB.Collection("saved_filters").Find(context.TODO(), filter, opts)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var filters []models.SavedFilter
+
+	if err = cursor.All(context.TODO(), &filters); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if filters == nil {
+		filters = []models.SavedFilter{}
+	}
+
+	for i := range filters {
+		if err := h.countFilter(&filters[i]); err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": filters})
+}
+
+func (h handler) GetFilter(c *gin.Context) {
+	filter := h.findFilter(c)
+
+	if filter == nil {
+		return
+	}
+
+	if err := h.countFilter(filter); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": filter})
+}
+
+func (h handler) AddFilter(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var input filterInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	if out := input.Filter.validate(); len(out) > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		return
+	}
+
+	sort := "updated_at"
+	order := "desc"
+	pinned := false
+	now := time.Now()
+
+	if input.Sort != nil {
+		sort = *input.Sort
+	}
+
+	if input.Order != nil {
+		order = *input.Order
+	}
+
+	if input.Pinned != nil {
+		pinned = *input.Pinned
+	}
+
+	taskFilter := models.TaskFilter(*input.Filter)
+
+	f := models.SavedFilter{
+		UserId:    uid,
+		Name:      input.Name,
+		Filter:    &taskFilter,
+		Sort:      &sort,
+		Order:     &order,
+		Pinned:    &pinned,
+		CreatedAt: &now,
+		UpdatedAt: &now,
+	}
+
+	req, err := h.DB.Collection("saved_filters").InsertOne(context.TODO(), f)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "filter saved successfully",
+		"id":      req.InsertedID,
+	})
+}
+
+func (h handler) UpdateFilter(c *gin.Context) {
+	var input updateFilterInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	if input.Filter != nil {
+		if out := input.Filter.validate(); len(out) > 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+			return
+		}
+	}
+
+	filter := h.findFilter(c)
+
+	if filter == nil {
+		return
+	}
+
+	data := bson.D{{Key: "updated_at", Value: time.Now()}}
+
+	if input.Name != nil {
+		data = append(data, bson.E{Key: "name", Value: input.Name})
+	}
+
+	if input.Filter != nil {
+		data = append(data, bson.E{Key: "filter", Value: models.TaskFilter(*input.Filter)})
+	}
+
+	if input.Sort != nil {
+		data = append(data, bson.E{Key: "sort", Value: input.Sort})
+	}
+
+	if input.Order != nil {
+		data = append(data, bson.E{Key: "order", Value: input.Order})
+	}
+
+	if input.Pinned != nil {
+		data = append(data, bson.E{Key: "pinned", Value: input.Pinned})
+	}
+
+	update := bson.D{{Key: "$set", Value: data}}
+
+	if _, err := h.DB.Collection("saved_filters").UpdateByID(context.TODO(), filter.Id, update); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "filter updated successfully",
+	})
+}
+
+func (h handler) DeleteFilter(c *gin.Context) {
+	filter := h.findFilter(c)
+
+	if filter == nil {
+		return
+	}
+
+	if _, err := h.DB.Collection("saved_filters").DeleteOne(context.TODO(), bson.D{{Key: "_id", Value: filter.Id}}); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "filter deleted successfully",
+	})
+}
+
+func (h handler) findFilter(c *gin.Context) *models.SavedFilter {
+	filterId := c.Param("id")
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return nil
+	}
+
+	id, err := primitive.ObjectIDFromHex(filterId)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return nil
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "user_id", Value: uid},
+	}
+
+	var savedFilter models.SavedFilter
+
+	if err := h.DB.Collection("saved_filters").FindOne(context.TODO(), filter).Decode(&savedFilter); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("filter not found with id '%s'", filterId),
+			})
+
+			return nil
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return nil
+	}
+
+	return &savedFilter
+}
+
+func (h handler) countFilter(savedFilter *models.SavedFilter) error {
+	query, err := savedFilterQuery(savedFilter)
+
+	if err != nil {
+		return err
+	}
+
+	count, err := h.DB.Collection("tasks").CountDocuments(context.TODO(), query)
+
+	if err != nil {
+		return err
+	}
+
+	savedFilter.Count = &count
+	return nil
+}
+
+func savedFilterQuery(savedFilter *models.SavedFilter) (bson.M, error) {
+	var f tasksFilter
+
+	if savedFilter.Filter != nil {
+		f = tasksFilter(*savedFilter.Filter)
+	}
+
+	return f.query(savedFilter.UserId, "created")
+}
@@ -0,0 +1,501 @@
+package tasks
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/history"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/timetracking"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	syncTokenPrefix  = "v1:"
+	syncPagePrefix   = "v2:"
+	syncPageSize     = 500
+	syncOverlap      = 5 * time.Second
+	maxSyncMutations = 500
+
+	syncApplied  = "applied"
+	syncConflict = "conflict"
+	syncRejected = "rejected"
+)
+
+type syncMutation struct {
+	ClientId *string         `json:"client_id" binding:"required"`
+	Op       *string         `json:"op" binding:"required,oneof=create update delete"`
+	Id       *string         `json:"id"`
+	Version  *int64          `json:"version"`
+	Data     json.RawMessage `json:"data"`
+}
+
+type syncInput struct {
+	Mutations []syncMutation `json:"mutations" binding:"required,dive"`
+}
+
+type syncResult struct {
+	ClientId string              `json:"client_id"`
+	Id       *primitive.ObjectID `json:"id,omitempty"`
+	Status   string              `json:"status"`
+	Version  *int64              `json:"version,omitempty"`
+	Error    string              `json:"error,omitempty"`
+	Errors   []utils.ErrorMsg    `json:"errors,omitempty"`
+	Current  *models.Task        `json:"current,omitempty"`
+}
+
+func (h handler) GetChanges(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	since, after, err := parseSyncToken(c.Query("token"))
+
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid sync token"})
+		return
+	}
+
+	now := time.Now()
+	expired := since != nil && since.Before(now.Add(-history.TombstoneRetention()))
+	full := since == nil || expired || after != nil
+	started := now
+
+	if after != nil && !expired {
+		started = *since
+	} else {
+		after = nil
+	}
+
+	tasksFilter := bson.D{{Key: "user_id", Value: uid}}
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	if full {
+		opts = options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(syncPageSize + 1)
+
+		if after != nil {
+			tasksFilter = append(tasksFilter, bson.E{Key: "_id", Value: bson.D{{Key: "$gt", Value: after}}})
+		}
+	} else {
+		tasksFilter = append(tasksFilter, bson.E{Key: "updated_at", Value: bson.D{{Key: "$gte", Value: since}}})
+	}
+
+	tasksCollection := h.DB.Collection("tasks")
+	cursor, err := tasksCollection.Find(context.TODO(), tasksFilter, opts)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var tasks []models.Task
+
+	if err = cursor.All(context.TODO(), &tasks); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if tasks == nil {
+		tasks = []models.Task{}
+	}
+
+	syncToken := newSyncToken(started.Add(-syncOverlap))
+	hasMore := full && len(tasks) > syncPageSize
+
+	if hasMore {
+		tasks = tasks[:syncPageSize]
+		syncToken = newSyncPageToken(started, *tasks[len(tasks)-1].Id)
+	}
+
+	deletedIds := []primitive.ObjectID{}
+
+	if !full {
+		tombstonesFilter := bson.D{
+			{Key: "user_id", Value: uid},
+			{Key: "deleted_at", Value: bson.D{{Key: "$gte", Value: since}}},
+		}
+
+		cursor, err := h.DB.Collection(history.TombstonesCollection).Find(context.TODO(), tombstonesFilter)
+
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		var tombstones []models.Tombstone
+
+		if err = cursor.All(context.TODO(), &tombstones); err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		for _, tombstone := range tombstones {
+			deletedIds = append(deletedIds, *tombstone.TaskId)
+		}
+	}
+
+	var settings *models.Settings
+
+	if utils.HasScope(c, "settings:read") {
+		var s models.Settings
+		settingsFilter := bson.D{{Key: "user_id", Value: uid}}
+		err := h.DB.Collection("settings").FindOne(context.TODO(), settingsFilter).Decode(&s)
+
+		if err != nil && err != mongo.ErrNoDocuments {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		if err == nil && (full || s.UpdatedAt == nil || !s.UpdatedAt.Before(*since)) {
+			settings = &s
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sync_token":       syncToken,
+		"full":             full,
+		"has_more":         hasMore,
+		"tasks":            tasks,
+		"deleted_task_ids": deletedIds,
+		"settings":         settings,
+	})
+}
+
+func (h handler) ApplyMutations(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var input syncInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	if len(input.Mutations) > maxSyncMutations {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("a sync request can contain at most %d mutations", maxSyncMutations),
+		})
+
+		return
+	}
+
+	results := make([]syncResult, 0, len(input.Mutations))
+
+	for _, mutation := range input.Mutations {
+		var result syncResult
+		var err error
+
+		switch *mutation.Op {
+		case "create":
+			result, err = h.syncCreate(c, uid, mutation)
+		case "update":
+			result, err = h.syncUpdate(c, uid, mutation)
+		case "delete":
+			result, err = h.syncDelete(c, uid, mutation)
+		}
+
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		result.ClientId = *mutation.ClientId
+		results = append(results, result)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+func (h handler) syncCreate(c *gin.Context, uid *primitive.ObjectID, mutation syncMutation) (syncResult, error) {
+	tasksCollection := h.DB.Collection("tasks")
+
+	existing, err := h.findSyncedTask(bson.D{
+		{Key: "user_id", Value: uid},
+		{Key: "client_id", Value: mutation.ClientId},
+	})
+
+	if err != nil || existing != nil {
+		return appliedResult(existing), err
+	}
+
+	var input addInput
+
+	if err := json.Unmarshal(mutation.Data, &input); err != nil {
+		return syncResult{Status: syncRejected, Error: "invalid task data"}, nil
+	}
+
+	if err := binding.Validator.ValidateStruct(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			return syncResult{Status: syncRejected, Errors: utils.FillErrors(ve)}, nil
+		}
+
+		return syncResult{Status: syncRejected, Error: err.Error()}, nil
+	}
+
+	t := newTask(uid, input)
+	t.ClientId = mutation.ClientId
+	req, err := tasksCollection.InsertOne(context.TODO(), t)
+
+	if mongo.IsDuplicateKeyError(err) {
+		existing, err := h.findSyncedTask(bson.D{
+			{Key: "user_id", Value: uid},
+			{Key: "client_id", Value: mutation.ClientId},
+		})
+
+		return appliedResult(existing), err
+	}
+
+	if err != nil {
+		return syncResult{}, err
+	}
+
+	id := req.InsertedID.(primitive.ObjectID)
+	fields, err := history.ToMap(t)
+
+	if err != nil {
+		return syncResult{}, err
+	}
+
+	if _, err := h.recordHistory(c, id, uid, models.TaskActionCreated, history.Diff(bson.M{}, fields)); err != nil {
+		return syncResult{}, err
+	}
+
+	return syncResult{Status: syncApplied, Id: &id, Version: t.Version}, nil
+}
+
+func (h handler) syncUpdate(c *gin.Context, uid *primitive.ObjectID, mutation syncMutation) (syncResult, error) {
+	id, result := syncTaskId(mutation)
+
+	if id == nil {
+		return result, nil
+	}
+
+	var input updateInput
+
+	if err := json.Unmarshal(mutation.Data, &input); err != nil {
+		return syncResult{Status: syncRejected, Error: "invalid task data"}, nil
+	}
+
+	data := input.data()
+
+	update := bson.D{
+		{Key: "$set", Value: data},
+		{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+	}
+
+	if input.Done.Set {
+		update = withDoneAt(update, input.Done.Valid && input.Done.Value)
+	}
+
+	before, result, err := h.syncWrite(uid, *id, mutation, update)
+
+	if err != nil || before == nil {
+		return result, err
+	}
+
+	if _, err := h.recordHistory(c, *id, uid, models.TaskActionUpdated, history.Diff(before, data)); err != nil {
+		return syncResult{}, err
+	}
+
+	return result, nil
+}
+
+func (h handler) syncDelete(c *gin.Context, uid *primitive.ObjectID, mutation syncMutation) (syncResult, error) {
+	id, result := syncTaskId(mutation)
+
+	if id == nil {
+		return result, nil
+	}
+
+	now := time.Now()
+	before, result, err := h.syncWrite(uid, *id, mutation, trashUpdate(now))
+
+	if err != nil || before == nil {
+		if result.Current != nil && *result.Current.Status == "deleted" {
+			return syncResult{Status: syncApplied, Id: id, Version: result.Current.Version}, nil
+		}
+
+		return result, err
+	}
+
+	if _, err := timetracking.Stop(context.TODO(), h.DB, *uid, id); err != nil {
+		return syncResult{}, err
+	}
+
+	if _, err := h.recordHistory(c, *id, uid, models.TaskActionDeleted, trashChanges(before, now)); err != nil {
+		return syncResult{}, err
+	}
+
+	return result, nil
+}
+
+func (h handler) syncWrite(uid *primitive.ObjectID, id primitive.ObjectID, mutation syncMutation, update interface{}) (bson.M, syncResult, error) {
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "user_id", Value: uid},
+		{Key: "status", Value: "created"},
+	}
+
+	if mutation.Version != nil {
+		filter = append(filter, utils.VersionFilter(*mutation.Version))
+	}
+
+	var before bson.M
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+	err := h.DB.Collection("tasks").FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&before)
+
+	if err == nil {
+		version := utils.VersionOf(before) + 1
+		return before, syncResult{Status: syncApplied, Id: &id, Version: &version}, nil
+	}
+
+	if err != mongo.ErrNoDocuments {
+		return nil, syncResult{}, err
+	}
+
+	current, err := h.findSyncedTask(bson.D{
+		{Key: "_id", Value: id},
+		{Key: "user_id", Value: uid},
+	})
+
+	if err != nil {
+		return nil, syncResult{}, err
+	}
+
+	if current == nil {
+		return nil, syncResult{Status: syncRejected, Id: &id, Error: "task not found"}, nil
+	}
+
+	return nil, syncResult{
+		Status:  syncConflict,
+		Id:      &id,
+		Version: current.Version,
+		Error:   "task was modified on the server",
+		Current: current,
+	}, nil
+}
+
+func (h handler) findSyncedTask(filter bson.D) (*models.Task, error) {
+	var task models.Task
+
+	if err := h.DB.Collection("tasks").FindOne(context.TODO(), filter).Decode(&task); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+func appliedResult(task *models.Task) syncResult {
+	if task == nil {
+		return syncResult{}
+	}
+
+	return syncResult{Status: syncApplied, Id: task.Id, Version: task.Version}
+}
+
+func syncTaskId(mutation syncMutation) (*primitive.ObjectID, syncResult) {
+	if mutation.Id == nil {
+		return nil, syncResult{Status: syncRejected, Error: "id is required"}
+	}
+
+	id, err := primitive.ObjectIDFromHex(*mutation.Id)
+
+	if err != nil {
+		return nil, syncResult{Status: syncRejected, Error: fmt.Sprintf("invalid task id '%s'", *mutation.Id)}
+	}
+
+	return &id, syncResult{}
+}
+
+func newSyncToken(t time.Time) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(syncTokenPrefix + strconv.FormatInt(t.UnixMilli(), 10)))
+}
+
+func newSyncPageToken(started time.Time, after primitive.ObjectID) string {
+	value := syncPagePrefix + strconv.FormatInt(started.UnixMilli(), 10) + ":" + after.Hex()
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+func parseSyncToken(token string) (*time.Time, *primitive.ObjectID, error) {
+	if token == "" {
+		return nil, nil, nil
+	}
+
+	invalid := errors.New("invalid sync token")
+	data, err := base64.RawURLEncoding.DecodeString(token)
+
+	if err != nil {
+		return nil, nil, invalid
+	}
+
+	value := string(data)
+	var after *primitive.ObjectID
+
+	switch {
+	case strings.HasPrefix(value, syncTokenPrefix):
+		value = strings.TrimPrefix(value, syncTokenPrefix)
+	case strings.HasPrefix(value, syncPagePrefix):
+		parts := strings.Split(strings.TrimPrefix(value, syncPagePrefix), ":")
+
+		if len(parts) != 2 {
+			return nil, nil, invalid
+		}
+
+		id, err := primitive.ObjectIDFromHex(parts[1])
+
+		if err != nil {
+			return nil, nil, invalid
+		}
+
+		value = parts[0]
+		after = &id
+	default:
+		return nil, nil, invalid
+	}
+
+	millis, err := strconv.ParseInt(value, 10, 64)
+
+	if err != nil {
+		return nil, nil, invalid
+	}
+
+	since := time.UnixMilli(millis)
+
+	return &since, after, nil
+}
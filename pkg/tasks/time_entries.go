@@ -0,0 +1,351 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/timetracking"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const maxTimeEntry = 24 * time.Hour
+
+type startTimerInput struct {
+	Note *string `json:"note" binding:"omitempty,max=500"`
+}
+
+type addTimeEntryInput struct {
+	StartedAt *time.Time `json:"started_at" binding:"required"`
+	EndedAt   *time.Time `json:"ended_at" binding:"required_without=Minutes"`
+	Minutes   *int       `json:"minutes" binding:"required_without=EndedAt,omitempty,min=1,max=1440"`
+	Note      *string    `json:"note" binding:"omitempty,max=500"`
+}
+
+func (h handler) GetTimeEntries(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	taskId := h.findTimeTask(c, uid)
+
+	if taskId == nil {
+		return
+	}
+
+	filter := bson.D{
+		{Key: "user_id", Value: uid},
+		{Key: "task_id", Value: taskId},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "started_at", Value: -1}})
+	cursor, err := h.DB.Collection(timetracking.Collection).Find(context.TODO(), filter, opts)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var entries []models.TimeEntry
+
+	if err = cursor.All(context.TODO(), &entries); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if entries == nil {
+		entries = []models.TimeEntry{}
+	}
+
+	now := time.Now()
+	var total int64
+
+	for _, entry := range entries {
+		total += timetracking.Elapsed(entry, now)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":            entries,
+		"tracked_seconds": total,
+	})
+}
+
+func (h handler) AddTimeEntry(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var input addTimeEntryInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	endedAt := input.EndedAt
+
+	if endedAt == nil {
+		end := input.StartedAt.Add(time.Minute * time.Duration(*input.Minutes))
+		endedAt = &end
+	}
+
+	now := time.Now()
+	duration := endedAt.Sub(*input.StartedAt)
+
+	if duration <= 0 || duration > maxTimeEntry {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": []utils.ErrorMsg{
+			{
+				Field:   "EndedAt",
+				Message: "this field must be later than StartedAt and at most 24 hours after it",
+			},
+		}})
+
+		return
+	}
+
+	if endedAt.After(now) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": []utils.ErrorMsg{
+			{
+				Field:   "EndedAt",
+				Message: "this field must not be in the future",
+			},
+		}})
+
+		return
+	}
+
+	taskId := h.findTimeTask(c, uid)
+
+	if taskId == nil {
+		return
+	}
+
+	seconds := int64(duration.Seconds())
+	source := models.TimeEntrySourceManual
+
+	entry := models.TimeEntry{
+		UserId:    uid,
+		TaskId:    taskId,
+		StartedAt: input.StartedAt,
+		EndedAt:   endedAt,
+		Seconds:   &seconds,
+		Note:      input.Note,
+		Source:    &source,
+		CreatedAt: &now,
+		UpdatedAt: &now,
+	}
+
+	result, err := h.DB.Collection(timetracking.Collection).InsertOne(context.TODO(), entry)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	id := result.InsertedID.(primitive.ObjectID)
+	entry.Id = &id
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "time entry added successfully",
+		"data":    entry,
+	})
+}
+
+func (h handler) DeleteTimeEntry(c *gin.Context) {
+	entryId := c.Param("entryId")
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	taskId := h.findTimeTask(c, uid)
+
+	if taskId == nil {
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(entryId)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "user_id", Value: uid},
+		{Key: "task_id", Value: taskId},
+	}
+
+	result, err := h.DB.Collection(timetracking.Collection).DeleteOne(context.TODO(), filter)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if result.DeletedCount == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("time entry not found with id '%s'", entryId),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "time entry deleted successfully",
+	})
+}
+
+func (h handler) StartTimer(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var input startTimerInput
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			var ve validator.ValidationErrors
+
+			if errors.As(err, &ve) {
+				out := utils.FillErrors(ve)
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+			} else {
+				c.AbortWithError(http.StatusBadRequest, err)
+			}
+
+			return
+		}
+	}
+
+	taskId := h.findTimeTask(c, uid)
+
+	if taskId == nil {
+		return
+	}
+
+	entry, err := timetracking.Start(context.TODO(), h.DB, *uid, *taskId, input.Note)
+
+	if mongo.IsDuplicateKeyError(err) {
+		running, err := timetracking.Running(context.TODO(), h.DB, *uid)
+
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"error": "a timer is already running, stop it before starting a new one",
+			"data":  running,
+		})
+
+		return
+	}
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "timer started successfully",
+		"data":    entry,
+	})
+}
+
+func (h handler) StopTimer(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	taskId := h.findTimeTask(c, uid)
+
+	if taskId == nil {
+		return
+	}
+
+	entry, err := timetracking.Stop(context.TODO(), h.DB, *uid, taskId)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if entry == nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("no timer running for task with id '%s'", taskId.Hex()),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "timer stopped successfully",
+		"data":    entry,
+	})
+}
+
+func (h handler) findTimeTask(c *gin.Context, uid *primitive.ObjectID) *primitive.ObjectID {
+	taskId := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(taskId)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return nil
+	}
+
+	filter := bson.D{
+		{Key: "user_id", Value: uid},
+		{Key: "_id", Value: id},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{"created", "archived"}}}},
+	}
+
+	count, err := h.DB.Collection("tasks").CountDocuments(context.TODO(), filter)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return nil
+	}
+
+	if count == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("task not found with id '%s'", taskId),
+		})
+
+		return nil
+	}
+
+	return &id
+}
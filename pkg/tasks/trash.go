@@ -0,0 +1,60 @@
+package tasks
+
+import (
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var incVersion = bson.D{{Key: "$add", Value: bson.A{bson.D{{Key: "$ifNull", Value: bson.A{"$version", 0}}}, 1}}}
+
+func trashUpdate(now time.Time) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "trashed_from", Value: "$status"},
+			{Key: "status", Value: "deleted"},
+			{Key: "deleted_at", Value: now},
+			{Key: "updated_at", Value: now},
+			{Key: "version", Value: incVersion},
+		}}},
+	}
+}
+
+func restoreUpdate(now time.Time) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "status", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$trashed_from", "created"}}}},
+			{Key: "updated_at", Value: now},
+			{Key: "version", Value: incVersion},
+		}}},
+		{{Key: "$unset", Value: bson.A{"deleted_at", "trashed_from"}}},
+	}
+}
+
+func trashChanges(before bson.M, now time.Time) []models.FieldChange {
+	status, _ := before["status"].(string)
+
+	return statusChange(
+		status,
+		"deleted",
+		models.FieldChange{Field: "deleted_at", New: now},
+		models.FieldChange{Field: "trashed_from", New: status},
+	)
+}
+
+func restoreChanges(before bson.M) []models.FieldChange {
+	status, ok := before["trashed_from"].(string)
+
+	if !ok {
+		status = "created"
+	}
+
+	return statusChange(
+		"deleted",
+		status,
+		models.FieldChange{Field: "deleted_at", Old: before["deleted_at"]},
+		models.FieldChange{Field: "trashed_from", Old: before["trashed_from"]},
+	)
+}
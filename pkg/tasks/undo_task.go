@@ -0,0 +1,251 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/history"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+type undoInput struct {
+	Token *string `json:"token" binding:"required"`
+}
+
+var (
+	errUndoConflict = errors.New("the task was changed after this change and can't be undone")
+	errUndoNotFound = errors.New("the task of this change no longer exists")
+)
+
+func (h handler) UndoTask(c *gin.Context) {
+	var input undoInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	historyCollection := h.DB.Collection(history.Collection)
+
+	filter := bson.D{
+		{Key: "user_id", Value: uid},
+		{Key: "undo_token_hash", Value: utils.HashToken(*input.Token)},
+	}
+
+	cursor, err := historyCollection.Find(context.TODO(), filter)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var entries []models.TaskHistory
+
+	if err = cursor.All(context.TODO(), &entries); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if len(entries) == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "undo token not found"})
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.UndoneAt != nil {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "this change has already been undone"})
+			return
+		}
+
+		if entry.UndoExpiresAt == nil || entry.UndoExpiresAt.Before(time.Now()) {
+			c.AbortWithStatusJSON(http.StatusGone, gin.H{"error": "undo token has expired"})
+			return
+		}
+	}
+
+	source := c.Request.Method + " " + c.FullPath()
+	action := models.TaskActionReverted
+	reverts := make([]models.TaskHistory, 0, len(entries))
+
+	for _, entry := range entries {
+		reverts = append(reverts, models.TaskHistory{
+			TaskId:   entry.TaskId,
+			UserId:   uid,
+			ActorId:  uid,
+			Action:   &action,
+			Source:   &source,
+			Changes:  revertChanges(entry),
+			RevertOf: entry.Id,
+		})
+	}
+
+	undoToken, err := history.NewUndoToken(reverts)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	wc := writeconcern.Majority()
+	txnOptions := options.Transaction().SetWriteConcern(wc)
+	session, err := h.Client.StartSession()
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	defer session.EndSession(context.TODO())
+
+	_, err = session.WithTransaction(context.TODO(), func(ctx mongo.SessionContext) (interface{}, error) {
+		for i, entry := range entries {
+			if err := h.revertEntry(ctx, uid, entry, reverts[i].Changes); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, history.RecordMany(ctx, h.DB, reverts)
+	}, txnOptions)
+
+	if err != nil {
+		if errors.Is(err, errUndoConflict) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": errUndoConflict.Error()})
+			return
+		}
+
+		if errors.Is(err, errUndoNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": errUndoNotFound.Error()})
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	for _, revert := range reverts {
+		publishTaskEvent(*uid, *revert.TaskId, action, revert.Changes)
+	}
+
+	c.JSON(http.StatusOK, withUndoToken(gin.H{
+		"message": "change undone successfully",
+	}, undoToken))
+}
+
+func revertChanges(entry models.TaskHistory) []models.FieldChange {
+	if *entry.Action == models.TaskActionCreated {
+		return statusChange("created", "deleted", models.FieldChange{Field: "deleted_at", New: time.Now()})
+	}
+
+	changes := make([]models.FieldChange, 0, len(entry.Changes))
+
+	for _, change := range entry.Changes {
+		changes = append(changes, models.FieldChange{
+			Field: change.Field,
+			Old:   change.New,
+			New:   change.Old,
+		})
+	}
+
+	return changes
+}
+
+func (h handler) revertEntry(ctx mongo.SessionContext, uid *primitive.ObjectID, entry models.TaskHistory, changes []models.FieldChange) error {
+	historyCollection := h.DB.Collection(history.Collection)
+	var latest models.TaskHistory
+
+	latestFilter := bson.D{{Key: "task_id", Value: entry.TaskId}}
+	latestOpts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+
+	if err := historyCollection.FindOne(ctx, latestFilter, latestOpts).Decode(&latest); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return errUndoNotFound
+		}
+
+		return err
+	}
+
+	if *latest.Id != *entry.Id {
+		return errUndoConflict
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: entry.TaskId},
+		{Key: "user_id", Value: uid},
+	}
+
+	set := bson.D{{Key: "updated_at", Value: time.Now()}}
+	unset := bson.D{}
+	var doneAt *time.Time
+
+	for _, change := range changes {
+		filter = append(filter, bson.E{Key: change.Field, Value: change.Old})
+
+		if change.New == nil {
+			unset = append(unset, bson.E{Key: change.Field, Value: ""})
+		} else {
+			set = append(set, bson.E{Key: change.Field, Value: change.New})
+		}
+
+		if change.Field == "done" {
+			if done, _ := change.New.(bool); done {
+				now := time.Now()
+				doneAt = &now
+			} else {
+				unset = append(unset, bson.E{Key: "done_at", Value: ""})
+			}
+		}
+	}
+
+	update := bson.D{
+		{Key: "$set", Value: set},
+		{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+	}
+
+	if len(unset) > 0 {
+		update = append(update, bson.E{Key: "$unset", Value: unset})
+	}
+
+	if doneAt != nil {
+		update = append(update, bson.E{Key: "$min", Value: bson.D{{Key: "done_at", Value: doneAt}}})
+	}
+
+	result, err := h.DB.Collection("tasks").UpdateOne(ctx, filter, update)
+
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errUndoConflict
+	}
+
+	undone := bson.D{{Key: "$set", Value: bson.D{{Key: "undone_at", Value: time.Now()}}}}
+	_, err = historyCollection.UpdateByID(ctx, entry.Id, undone)
+
+	return err
+}
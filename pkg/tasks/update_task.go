@@ -7,11 +7,15 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Bryan-an/tasker-backend/pkg/common/history"
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
 	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type updateInput struct {
@@ -58,6 +62,13 @@ func (h handler) UpdateTask(c *gin.Context) {
 		return
 	}
 
+	ifMatch, err := utils.IfMatch(c)
+
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	tasksCollection := h.DB.Collection("tasks")
 
 	filter := bson.D{
@@ -66,6 +77,78 @@ func (h handler) UpdateTask(c *gin.Context) {
 		{Key: "status", Value: "created"},
 	}
 
+	data := input.data()
+
+	update := bson.D{
+		{
+			Key:   "$set",
+			Value: data,
+		},
+		{
+			Key:   "$inc",
+			Value: bson.D{{Key: "version", Value: 1}},
+		},
+	}
+
+	if input.Done.Set {
+		update = withDoneAt(update, input.Done.Valid && input.Done.Value)
+	}
+
+	taskFilter := filter
+
+	if ifMatch != nil {
+		filter = append(filter, utils.VersionFilter(*ifMatch))
+	}
+
+	var before bson.M
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+	if err := tasksCollection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&before); err != nil {
+		if err == mongo.ErrNoDocuments {
+			if ifMatch != nil {
+				count, err := tasksCollection.CountDocuments(context.TODO(), taskFilter)
+
+				if err != nil {
+					c.AbortWithError(http.StatusInternalServerError, err)
+					return
+				}
+
+				if count > 0 {
+					c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{
+						"error": "task was modified by another request, fetch it again and retry",
+					})
+
+					return
+				}
+			}
+
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("task not found with id '%s'", uid),
+			})
+
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	version := utils.VersionOf(before) + 1
+	utils.SetETag(c, &version)
+
+	undoToken, err := h.recordHistory(c, id, uid, models.TaskActionUpdated, history.Diff(before, data))
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, withUndoToken(gin.H{
+		"message": "task updated successfully",
+	}, undoToken))
+}
+
+func (input updateInput) data() bson.M {
 	data := bson.M{
 		"updated_at": time.Now(),
 	}
@@ -150,29 +233,5 @@ func (h handler) UpdateTask(c *gin.Context) {
 		}
 	}
 
-	update := bson.D{
-		{
-			Key:   "$set",
-			Value: data,
-		},
-	}
-
-	result, err := tasksCollection.UpdateOne(context.TODO(), filter, update)
-
-	if err != nil {
-		c.AbortWithError(http.StatusInternalServerError, err)
-		return
-	}
-
-	if result.MatchedCount == 0 {
-		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
-			"error": fmt.Sprintf("task not found with id '%s'", uid),
-		})
-
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"message": "task updated successfully",
-	})
+	return data
 }
@@ -0,0 +1,157 @@
+package timetracking
+
+import (
+	"context"
+	"math"
+	"net/http"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/timetracking"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var levels = []string{"low", "medium", "high"}
+
+type estimateRow struct {
+	Complexity *string `bson:"_id"`
+	Tasks      int64   `bson:"tasks"`
+	Estimated  float64 `bson:"estimated"`
+	Actual     float64 `bson:"actual"`
+}
+
+type estimate struct {
+	Complexity       string   `json:"complexity"`
+	Tasks            int64    `json:"tasks"`
+	EstimatedSeconds int64    `json:"estimated_seconds"`
+	ActualSeconds    int64    `json:"actual_seconds"`
+	AverageEstimated int64    `json:"average_estimated_seconds"`
+	AverageActual    int64    `json:"average_actual_seconds"`
+	Ratio            *float64 `json:"ratio"`
+}
+
+func (h handler) GetEstimates(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	r := h.parseRange(c, uid, 90)
+
+	if r == nil {
+		return
+	}
+
+	rows, err := h.estimateRows(*uid, r)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	byLevel := make(map[string]estimateRow, len(rows))
+
+	for _, row := range rows {
+		key := "none"
+
+		if row.Complexity != nil {
+			key = *row.Complexity
+		}
+
+		existing := byLevel[key]
+		existing.Tasks += row.Tasks
+		existing.Estimated += row.Estimated
+		existing.Actual += row.Actual
+		byLevel[key] = existing
+	}
+
+	values := append([]string{}, levels...)
+
+	if _, ok := byLevel["none"]; ok {
+		values = append(values, "none")
+	}
+
+	estimates := make([]estimate, 0, len(values))
+
+	for _, value := range values {
+		row := byLevel[value]
+
+		e := estimate{
+			Complexity:       value,
+			Tasks:            row.Tasks,
+			EstimatedSeconds: int64(row.Estimated),
+			ActualSeconds:    int64(row.Actual),
+		}
+
+		if row.Tasks > 0 {
+			e.AverageEstimated = int64(row.Estimated) / row.Tasks
+			e.AverageActual = int64(row.Actual) / row.Tasks
+		}
+
+		if row.Estimated > 0 {
+			ratio := math.Round(row.Actual/row.Estimated*100) / 100
+			e.Ratio = &ratio
+		}
+
+		estimates = append(estimates, e)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"from":      r.From.Format(dateLayout),
+		"to":        r.To.Format(dateLayout),
+		"timezone":  r.Loc.String(),
+		"estimates": estimates,
+	}})
+}
+
+func (h handler) estimateRows(uid primitive.ObjectID, r *dateRange) ([]estimateRow, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "user_id", Value: uid},
+			{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{"created", "archived"}}}},
+			{Key: "from", Value: bson.D{{Key: "$gte", Value: r.From}, {Key: "$lt", Value: r.End}}},
+			{Key: "to", Value: bson.D{{Key: "$ne", Value: nil}}},
+		}}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: timetracking.Collection},
+			{Key: "localField", Value: "_id"},
+			{Key: "foreignField", Value: "task_id"},
+			{Key: "as", Value: "entries"},
+		}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "complexity", Value: 1},
+			{Key: "estimated", Value: bson.D{{Key: "$divide", Value: bson.A{
+				bson.D{{Key: "$subtract", Value: bson.A{"$to", "$from"}}}, 1000,
+			}}}},
+			{Key: "actual", Value: bson.D{{Key: "$sum", Value: "$entries.seconds"}}},
+		}}},
+		{{Key: "$match", Value: bson.D{
+			{Key: "estimated", Value: bson.D{{Key: "$gt", Value: 0}}},
+			{Key: "actual", Value: bson.D{{Key: "$gt", Value: 0}}},
+		}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$complexity"},
+			{Key: "tasks", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "estimated", Value: bson.D{{Key: "$sum", Value: "$estimated"}}},
+			{Key: "actual", Value: bson.D{{Key: "$sum", Value: "$actual"}}},
+		}}},
+	}
+
+	cursor, err := h.DB.Collection("tasks").Aggregate(context.TODO(), pipeline)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []estimateRow
+
+	if err = cursor.All(context.TODO(), &rows); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
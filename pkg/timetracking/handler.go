@@ -0,0 +1,28 @@
+package timetracking
+
+import (
+	"github.com/Bryan-an/tasker-backend/pkg/common/middlewares"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type handler struct {
+	DB     *mongo.Database
+	Client *mongo.Client
+}
+
+func RegisterRoutes(r *gin.Engine, db *mongo.Database, client *mongo.Client) {
+	h := &handler{
+		DB:     db,
+		Client: client,
+	}
+
+	routes := r.Group("/api/v1/time")
+
+	routes.Use(middlewares.JwtAuthMiddleware(db))
+	routes.Use(middlewares.RequireScopes("tasks:read", "tasks:write"))
+	routes.GET("/timer", h.GetTimer)
+	routes.POST("/timer/stop", h.StopTimer)
+	routes.GET("/timesheet", h.GetTimesheet)
+	routes.GET("/estimates", h.GetEstimates)
+}
@@ -0,0 +1,87 @@
+package timetracking
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/timezone"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	dateLayout   = "2006-01-02"
+	maxRangeDays = 366
+)
+
+type dateRange struct {
+	From time.Time
+	To   time.Time
+	End  time.Time
+	Loc  *time.Location
+}
+
+func (h handler) parseRange(c *gin.Context, uid *primitive.ObjectID, days int) *dateRange {
+	queryParamsErrors := []utils.ErrorMsg{}
+	loc, err := timezone.ForUser(context.TODO(), h.DB, uid, c.Query("tz"))
+
+	if err == timezone.ErrInvalid {
+		queryParamsErrors = append(queryParamsErrors, utils.ErrorMsg{
+			Field:   "tz",
+			Message: "this query param must be a valid IANA time zone",
+		})
+
+		loc = time.UTC
+	} else if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return nil
+	}
+
+	now := time.Now().In(loc)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	from := to.AddDate(0, 0, 1-days)
+
+	if param := c.Query("to"); param != "" {
+		if to, err = time.ParseInLocation(dateLayout, param, loc); err != nil {
+			queryParamsErrors = append(queryParamsErrors, utils.ErrorMsg{
+				Field:   "to",
+				Message: "this query param must be a date in the format YYYY-MM-DD",
+			})
+		} else if c.Query("from") == "" {
+			from = to.AddDate(0, 0, 1-days)
+		}
+	}
+
+	if param := c.Query("from"); param != "" {
+		if from, err = time.ParseInLocation(dateLayout, param, loc); err != nil {
+			queryParamsErrors = append(queryParamsErrors, utils.ErrorMsg{
+				Field:   "from",
+				Message: "this query param must be a date in the format YYYY-MM-DD",
+			})
+		}
+	}
+
+	if len(queryParamsErrors) == 0 {
+		if to.Before(from) {
+			queryParamsErrors = append(queryParamsErrors, utils.ErrorMsg{
+				Field:   "to",
+				Message: "this query param must not be earlier than from",
+			})
+		} else if to.Sub(from) > time.Hour*24*maxRangeDays {
+			queryParamsErrors = append(queryParamsErrors, utils.ErrorMsg{
+				Field:   "to",
+				Message: fmt.Sprintf("the date range must not exceed %d days", maxRangeDays),
+			})
+		}
+	}
+
+	if len(queryParamsErrors) > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": queryParamsErrors})
+		return nil
+	}
+
+	return &dateRange{From: from, To: to, End: to.AddDate(0, 0, 1), Loc: loc}
+}
@@ -0,0 +1,63 @@
+package timetracking
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/timetracking"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+)
+
+func (h handler) GetTimer(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	entry, err := timetracking.Running(context.TODO(), h.DB, *uid)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if entry == nil {
+		c.JSON(http.StatusOK, gin.H{"data": nil})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":            entry,
+		"elapsed_seconds": timetracking.Elapsed(*entry, time.Now()),
+	})
+}
+
+func (h handler) StopTimer(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	entry, err := timetracking.Stop(context.TODO(), h.DB, *uid, nil)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if entry == nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "no timer is running"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "timer stopped successfully",
+		"data":    entry,
+	})
+}
@@ -0,0 +1,230 @@
+package timetracking
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/timetracking"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var timesheetCSVHeader = []string{
+	"date",
+	"task_id",
+	"task",
+	"started_at",
+	"ended_at",
+	"seconds",
+	"hours",
+	"source",
+	"note",
+}
+
+type timesheetEntry struct {
+	models.TimeEntry `bson:",inline"`
+	Tasks            []models.Task `json:"-" bson:"tasks"`
+}
+
+type timesheetRow struct {
+	Date      string              `json:"date"`
+	TaskId    *primitive.ObjectID `json:"task_id"`
+	Task      string              `json:"task"`
+	StartedAt *time.Time          `json:"started_at"`
+	EndedAt   *time.Time          `json:"ended_at"`
+	Seconds   int64               `json:"seconds"`
+	Running   bool                `json:"running"`
+	Source    string              `json:"source"`
+	Note      string              `json:"note"`
+}
+
+type timesheetTotal struct {
+	Key     string `json:"key"`
+	Task    string `json:"task,omitempty"`
+	Seconds int64  `json:"seconds"`
+}
+
+func (h handler) GetTimesheet(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+
+	if format != "json" && format != "csv" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": []utils.ErrorMsg{
+			{
+				Field:   "format",
+				Message: "this query param must be one of the following values: json csv",
+			},
+		}})
+
+		return
+	}
+
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	r := h.parseRange(c, uid, 7)
+
+	if r == nil {
+		return
+	}
+
+	rows, err := h.timesheetRows(*uid, r)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if format == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", "attachment; filename=\"timesheet-"+r.From.Format(dateLayout)+"-"+r.To.Format(dateLayout)+".csv\"")
+		c.Status(http.StatusOK)
+
+		if err := writeTimesheetCSV(c.Writer, rows); err != nil {
+			c.Error(err)
+		}
+
+		return
+	}
+
+	byDay := []timesheetTotal{}
+	byTask := []timesheetTotal{}
+	dayIndex := map[string]int{}
+	taskIndex := map[string]int{}
+	var total int64
+
+	for _, row := range rows {
+		total += row.Seconds
+
+		if i, ok := dayIndex[row.Date]; ok {
+			byDay[i].Seconds += row.Seconds
+		} else {
+			dayIndex[row.Date] = len(byDay)
+			byDay = append(byDay, timesheetTotal{Key: row.Date, Seconds: row.Seconds})
+		}
+
+		key := row.TaskId.Hex()
+
+		if i, ok := taskIndex[key]; ok {
+			byTask[i].Seconds += row.Seconds
+		} else {
+			taskIndex[key] = len(byTask)
+			byTask = append(byTask, timesheetTotal{Key: key, Task: row.Task, Seconds: row.Seconds})
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"from":     r.From.Format(dateLayout),
+		"to":       r.To.Format(dateLayout),
+		"timezone": r.Loc.String(),
+		"entries":  rows,
+		"totals": gin.H{
+			"seconds": total,
+			"days":    byDay,
+			"tasks":   byTask,
+		},
+	}})
+}
+
+func (h handler) timesheetRows(uid primitive.ObjectID, r *dateRange) ([]timesheetRow, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "user_id", Value: uid},
+			{Key: "started_at", Value: bson.D{{Key: "$gte", Value: r.From}, {Key: "$lt", Value: r.End}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "started_at", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "tasks"},
+			{Key: "localField", Value: "task_id"},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: "tasks"},
+		}}},
+	}
+
+	cursor, err := h.DB.Collection(timetracking.Collection).Aggregate(context.TODO(), pipeline)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []timesheetEntry
+
+	if err = cursor.All(context.TODO(), &entries); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	rows := make([]timesheetRow, 0, len(entries))
+
+	for _, entry := range entries {
+		row := timesheetRow{
+			Date:      entry.StartedAt.In(r.Loc).Format(dateLayout),
+			TaskId:    entry.TaskId,
+			StartedAt: entry.StartedAt,
+			EndedAt:   entry.EndedAt,
+			Seconds:   timetracking.Elapsed(entry.TimeEntry, now),
+			Running:   entry.Running != nil && *entry.Running,
+		}
+
+		if len(entry.Tasks) > 0 && entry.Tasks[0].Title != nil {
+			row.Task = *entry.Tasks[0].Title
+		}
+
+		if entry.Source != nil {
+			row.Source = *entry.Source
+		}
+
+		if entry.Note != nil {
+			row.Note = *entry.Note
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func writeTimesheetCSV(w http.ResponseWriter, rows []timesheetRow) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(timesheetCSVHeader); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		var endedAt string
+
+		if row.EndedAt != nil {
+			endedAt = row.EndedAt.Format(time.RFC3339)
+		}
+
+		record := []string{
+			row.Date,
+			row.TaskId.Hex(),
+			row.Task,
+			row.StartedAt.Format(time.RFC3339),
+			endedAt,
+			strconv.FormatInt(row.Seconds, 10),
+			strconv.FormatFloat(float64(row.Seconds)/3600, 'f', 2, 64),
+			row.Source,
+			row.Note,
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,93 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type addInput struct {
+	Name      *string    `json:"name" binding:"required"`
+	Scopes    *[]string  `json:"scopes" binding:"required,min=1,dive,oneof=tasks:read tasks:write settings:read settings:write users:read users:write webhooks:read webhooks:write"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (h handler) AddToken(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var input addInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	if input.ExpiresAt != nil && input.ExpiresAt.Before(time.Now()) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": []utils.ErrorMsg{
+			{
+				Field:   "ExpiresAt",
+				Message: "this field must be a date in the future",
+			},
+		}})
+
+		return
+	}
+
+	secret, err := utils.GetRandomString(32)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	token := utils.AccessTokenPrefix + secret
+	prefix := token[:len(utils.AccessTokenPrefix)+6]
+	hash := utils.HashToken(token)
+	status := "active"
+	now := time.Now()
+
+	t := models.AccessToken{
+		UserId:    uid,
+		Name:      input.Name,
+		Prefix:    &prefix,
+		Hash:      &hash,
+		Scopes:    input.Scopes,
+		Status:    &status,
+		ExpiresAt: input.ExpiresAt,
+		CreatedAt: &now,
+		UpdatedAt: &now,
+	}
+
+	tokensCollection := h.DB.Collection("access_tokens")
+	req, err := tokensCollection.InsertOne(context.TODO(), t)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "token created successfully, copy it now as it won't be shown again",
+		"id":      req.InsertedID,
+		"token":   token,
+	})
+}
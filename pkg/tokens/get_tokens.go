@@ -0,0 +1,51 @@
+package tokens
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (h handler) GetTokens(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	tokensCollection := h.DB.Collection("access_tokens")
+	var tokens []models.AccessToken
+
+	filter := bson.D{
+		{Key: "user_id", Value: uid},
+		{Key: "status", Value: "active"},
+	}
+
+	opts := options.Find().
+		SetProjection(bson.D{{Key: "hash", Value: 0}}).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := tokensCollection.Find(context.TODO(), filter, opts)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if err = cursor.All(context.TODO(), &tokens); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if tokens == nil {
+		tokens = []models.AccessToken{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tokens})
+}
@@ -0,0 +1,361 @@
+package users
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+type changeEmailInput struct {
+	Email *string `json:"email" binding:"required,email"`
+}
+
+type verifyEmailChangeInput struct {
+	Code *string `json:"code" binding:"required"`
+}
+
+const emailInUseMessage = "this email address is already in use"
+
+const tooManyCodeAttemptsMessage = "too many invalid codes, please request the email change again"
+
+func emailChangeMaxAttempts() int {
+	attempts, err := strconv.Atoi(os.Getenv("EMAIL_CHANGE_MAX_ATTEMPTS"))
+
+	if err != nil || attempts < 1 {
+		attempts = 5
+	}
+
+	return attempts
+}
+
+func (h handler) ChangeEmail(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var input changeEmailInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	usersColl := h.DB.Collection("users")
+	var user models.User
+
+	filter := bson.D{
+		{Key: "_id", Value: uid},
+		{Key: "status", Value: "active"},
+	}
+
+	if err := usersColl.FindOne(context.TODO(), filter).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("user not found with id '%s'", uid),
+			})
+
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if *user.Email == *input.Email {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "the new email address must be different from the current one",
+		})
+
+		return
+	}
+
+	count, err := usersColl.CountDocuments(context.TODO(), bson.D{{Key: "email", Value: input.Email}})
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if count > 0 {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": emailInUseMessage})
+		return
+	}
+
+	otp, err := utils.GetOTPToken(6)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	lifespan, err := strconv.Atoi(os.Getenv("EMAIL_VERIFICATION_CODE_EXPIRATION"))
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	expiresAt := time.Now().Add(time.Second * time.Duration(lifespan))
+	purpose := models.VerificationPurposeEmailChange
+
+	data := models.VerificationData{
+		Email:     input.Email,
+		Code:      &otp,
+		UserId:    uid,
+		Purpose:   &purpose,
+		ExpiresAt: &expiresAt,
+	}
+
+	verificationsColl := h.DB.Collection("verifications")
+
+	verificationsFilter := bson.D{
+		{Key: "user_id", Value: uid},
+		{Key: "purpose", Value: purpose},
+	}
+
+	if _, err := verificationsColl.DeleteMany(context.TODO(), verificationsFilter); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if _, err := verificationsColl.InsertOne(context.TODO(), data); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	err = utils.SendEmail(
+		*input.Email,
+		"Tasker - Confirm your new email address",
+		"<p>This is your code to confirm your new email address for Tasker: <b>"+otp+"</b></p>",
+	)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	err = utils.SendEmail(
+		*user.Email,
+		"Tasker - Email change requested",
+		"<p>A request was made to change the email address of your Tasker account to <b>"+
+			*input.Email+"</b>. If it wasn't you, please change your password.</p>",
+	)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "please check your new email address for the verification code",
+	})
+}
+
+func (h handler) VerifyEmailChange(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var input verifyEmailChangeInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	verificationsColl := h.DB.Collection("verifications")
+
+	verificationsFilter := bson.D{
+		{Key: "user_id", Value: uid},
+		{Key: "purpose", Value: models.VerificationPurposeEmailChange},
+	}
+
+	var data models.VerificationData
+
+	if err := verificationsColl.FindOne(context.TODO(), verificationsFilter).Decode(&data); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "there is no pending email change for this user",
+			})
+
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if data.Attempts != nil && *data.Attempts >= emailChangeMaxAttempts() {
+		if _, err := verificationsColl.DeleteMany(context.TODO(), verificationsFilter); err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": tooManyCodeAttemptsMessage})
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(*data.Code), []byte(*input.Code)) != 1 {
+		update := bson.D{{Key: "$inc", Value: bson.D{{Key: "attempts", Value: 1}}}}
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+		if err := verificationsColl.FindOneAndUpdate(context.TODO(), verificationsFilter, update, opts).Decode(&data); err != nil && err != mongo.ErrNoDocuments {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		if data.Attempts != nil && *data.Attempts >= emailChangeMaxAttempts() {
+			if _, err := verificationsColl.DeleteMany(context.TODO(), verificationsFilter); err != nil {
+				c.AbortWithError(http.StatusInternalServerError, err)
+				return
+			}
+
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": tooManyCodeAttemptsMessage})
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{
+			"error": "verification code provided is invalid, please look in your email for the code",
+		})
+
+		return
+	}
+
+	if data.ExpiresAt.Before(time.Now()) {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{
+			"error": "verification code has expired, please request the email change again",
+		})
+
+		return
+	}
+
+	usersColl := h.DB.Collection("users")
+	var user models.User
+
+	filter := bson.D{
+		{Key: "_id", Value: uid},
+		{Key: "status", Value: "active"},
+	}
+
+	if err := usersColl.FindOne(context.TODO(), filter).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("user not found with id '%s'", uid),
+			})
+
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	wc := writeconcern.Majority()
+	txnOptions := options.Transaction().SetWriteConcern(wc)
+	session, err := h.Client.StartSession()
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	defer session.EndSession(context.TODO())
+
+	_, err = session.WithTransaction(
+		context.TODO(),
+		func(ctx mongo.SessionContext) (interface{}, error) {
+			update := bson.D{
+				{
+					Key: "$set",
+					Value: bson.D{
+						{Key: "email", Value: data.Email},
+						{Key: "updated_at", Value: time.Now()},
+					},
+				},
+				{
+					Key:   "$unset",
+					Value: bson.D{{Key: "identities", Value: ""}},
+				},
+			}
+
+			if _, err := usersColl.UpdateOne(ctx, filter, update); err != nil {
+				return nil, err
+			}
+
+			if _, err := verificationsColl.DeleteMany(ctx, verificationsFilter); err != nil {
+				return nil, err
+			}
+
+			oldEmailFilter := bson.D{{Key: "email", Value: user.Email}}
+
+			if _, err := verificationsColl.DeleteMany(ctx, oldEmailFilter); err != nil {
+				return nil, err
+			}
+
+			_, err := h.DB.Collection("login_links").DeleteMany(ctx, oldEmailFilter)
+			return nil, err
+		},
+		txnOptions)
+
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": emailInUseMessage})
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	err = utils.SendEmail(
+		*user.Email,
+		"Tasker - Email address changed",
+		"<p>The email address of your Tasker account has been changed to <b>"+
+			*data.Email+"</b>. If it wasn't you, please contact support.</p>",
+	)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "email changed successfully",
+	})
+}
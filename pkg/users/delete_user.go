@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Bryan-an/tasker-backend/pkg/common/lifecycle"
 	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
@@ -20,37 +21,42 @@ func (h handler) DeleteUser(c *gin.Context) {
 	}
 
 	coll := h.DB.Collection("users")
+	filter := bson.D{{Key: "_id", Value: uid}}
+	result, err := lifecycle.Transition(context.TODO(), coll, filter, lifecycle.StatusUnsubscribed)
 
-	filter := bson.D{
-		{Key: "_id", Value: uid},
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("user not found with id '%s'", uid),
+		})
+
+		return
+	}
+
+	tokensFilter := bson.D{
+		{Key: "user_id", Value: uid},
 		{Key: "status", Value: "active"},
 	}
 
-	update := bson.D{
+	tokensUpdate := bson.D{
 		{
 			Key: "$set",
 			Value: bson.D{
-				{Key: "status", Value: "unsubscribed"},
+				{Key: "status", Value: "revoked"},
 				{Key: "updated_at", Value: time.Now()},
 			},
 		},
 	}
 
-	result, err := coll.UpdateOne(context.TODO(), filter, update)
-
-	if err != nil {
+	if _, err := h.DB.Collection("access_tokens").UpdateMany(context.TODO(), tokensFilter, tokensUpdate); err != nil {
 		c.AbortWithError(http.StatusInternalServerError, err)
 		return
 	}
 
-	if result.MatchedCount == 0 {
-		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
-			"error": fmt.Sprintf("user not found with id '%s'", uid),
-		})
-
-		return
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"messasge": "user unsubscribed successfully",
 	})
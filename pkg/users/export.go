@@ -0,0 +1,140 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/Bryan-an/tasker-backend/pkg/jobs"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (h handler) RequestExport(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	coll := h.DB.Collection("exports")
+
+	filter := bson.D{
+		{Key: "user_id", Value: uid},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{"pending", "processing"}}}},
+	}
+
+	count, err := coll.CountDocuments(context.TODO(), filter)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if count > 0 {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"error": "a data export is already in progress",
+		})
+
+		return
+	}
+
+	status := "pending"
+	now := time.Now()
+
+	export := models.Export{
+		UserId:    uid,
+		Status:    &status,
+		CreatedAt: &now,
+		UpdatedAt: &now,
+	}
+
+	req, err := coll.InsertOne(context.TODO(), export)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	go func() {
+		if err := jobs.ProcessDataExports(context.Background(), h.DB); err != nil {
+			log.Println(err.Error())
+		}
+	}()
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "your data export is being generated, we will email you a download link when it's ready",
+		"id":      req.InsertedID,
+	})
+}
+
+func (h handler) GetExport(c *gin.Context) {
+	exportId := c.Param("id")
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(exportId)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var export models.Export
+
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "user_id", Value: uid},
+	}
+
+	if err := h.DB.Collection("exports").FindOne(context.TODO(), filter).Decode(&export); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("export not found with id '%s'", exportId),
+			})
+
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": export})
+}
+
+func (h handler) DownloadExport(c *gin.Context) {
+	var export models.Export
+
+	filter := bson.D{
+		{Key: "token_hash", Value: utils.HashToken(c.Param("token"))},
+		{Key: "status", Value: "ready"},
+		{Key: "expires_at", Value: bson.D{{Key: "$gt", Value: time.Now()}}},
+	}
+
+	if err := h.DB.Collection("exports").FindOne(context.TODO(), filter).Decode(&export); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "export not found or download link expired",
+			})
+
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.FileAttachment(*export.FilePath, "tasker-export-"+export.CreatedAt.Format("2006-01-02")+".zip")
+}
@@ -17,11 +17,18 @@ func RegisterRoutes(r *gin.Engine, db *mongo.Database, client *mongo.Client) {
 		Client: client,
 	}
 
+	r.GET("/api/v1/users/export/download/:token", h.DownloadExport)
+
 	routes := r.Group("/api/v1/users")
 
-	routes.Use(middlewares.JwtAuthMiddleware())
+	routes.Use(middlewares.JwtAuthMiddleware(db))
+	routes.Use(middlewares.RequireScopes("users:read", "users:write"))
 	routes.GET("/", h.GetUser)
 	routes.PUT("/", h.ReplaceUser)
 	routes.PATCH("/", h.UpdateUser)
 	routes.DELETE("/", h.DeleteUser)
+	routes.POST("/email", h.ChangeEmail)
+	routes.POST("/email/verify", h.VerifyEmailChange)
+	routes.POST("/export", h.RequestExport)
+	routes.GET("/export/:id", h.GetExport)
 }
@@ -0,0 +1,114 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type addInput struct {
+	URL    *string   `json:"url" binding:"required,url"`
+	Events *[]string `json:"events" binding:"required,min=1,dive,oneof=task.created task.updated task.completed task.deleted task.restored task.archived task.unarchived task.purged settings.updated"`
+	Secret *string   `json:"secret" binding:"omitempty,min=16"`
+}
+
+func (h handler) AddWebhook(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var input addInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	if out := validateURL(*input.URL); out != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		return
+	}
+
+	secret := input.Secret
+
+	if secret == nil {
+		generated, err := utils.GetRandomString(32)
+
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		secret = &generated
+	}
+
+	active := true
+	failures := 0
+	now := time.Now()
+
+	w := models.Webhook{
+		UserId:              uid,
+		URL:                 input.URL,
+		Events:              input.Events,
+		Secret:              secret,
+		Active:              &active,
+		ConsecutiveFailures: &failures,
+		CreatedAt:           &now,
+		UpdatedAt:           &now,
+	}
+
+	req, err := h.DB.Collection("webhooks").InsertOne(context.TODO(), w)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "webhook created successfully, copy the secret now as it won't be shown again",
+		"id":      req.InsertedID,
+		"secret":  secret,
+	})
+}
+
+func validateURL(raw string) []utils.ErrorMsg {
+	u, err := url.Parse(raw)
+
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return []utils.ErrorMsg{
+			{
+				Field:   "URL",
+				Message: "this field must be an http or https URL",
+			},
+		}
+	}
+
+	if err := utils.CheckPublicHost(context.TODO(), u.Hostname()); err != nil {
+		return []utils.ErrorMsg{
+			{
+				Field:   "URL",
+				Message: "this field must point to a public host: " + err.Error(),
+			},
+		}
+	}
+
+	return nil
+}
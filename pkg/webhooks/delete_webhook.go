@@ -0,0 +1,33 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (h handler) DeleteWebhook(c *gin.Context) {
+	webhook := h.findWebhook(c)
+
+	if webhook == nil {
+		return
+	}
+
+	if _, err := h.DB.Collection("webhooks").DeleteOne(context.TODO(), bson.D{{Key: "_id", Value: webhook.Id}}); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	deliveriesFilter := bson.D{{Key: "webhook_id", Value: webhook.Id}}
+
+	if _, err := h.DB.Collection("webhook_deliveries").DeleteMany(context.TODO(), deliveriesFilter); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "webhook deleted successfully",
+	})
+}
@@ -0,0 +1,96 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (h handler) GetWebhooks(c *gin.Context) {
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	webhooksCollection := h.DB.Collection("webhooks")
+	var webhooks []models.Webhook
+
+	filter := bson.D{{Key: "user_id", Value: uid}}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := webhooksCollection.Find(context.TODO(), filter, opts)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if err = cursor.All(context.TODO(), &webhooks); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if webhooks == nil {
+		webhooks = []models.Webhook{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": webhooks})
+}
+
+func (h handler) GetWebhook(c *gin.Context) {
+	webhook := h.findWebhook(c)
+
+	if webhook == nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": webhook})
+}
+
+func (h handler) findWebhook(c *gin.Context) *models.Webhook {
+	webhookId := c.Param("id")
+	uid, err := utils.ExtractTokenID(c)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return nil
+	}
+
+	id, err := primitive.ObjectIDFromHex(webhookId)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return nil
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "user_id", Value: uid},
+	}
+
+	var webhook models.Webhook
+
+	if err := h.DB.Collection("webhooks").FindOne(context.TODO(), filter).Decode(&webhook); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("webhook not found with id '%s'", webhookId),
+			})
+
+			return nil
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return nil
+	}
+
+	return &webhook
+}
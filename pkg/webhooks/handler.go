@@ -0,0 +1,31 @@
+package webhooks
+
+import (
+	"github.com/Bryan-an/tasker-backend/pkg/common/middlewares"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type handler struct {
+	DB     *mongo.Database
+	Client *mongo.Client
+}
+
+func RegisterRoutes(r *gin.Engine, db *mongo.Database, client *mongo.Client) {
+	h := &handler{
+		DB:     db,
+		Client: client,
+	}
+
+	routes := r.Group("/api/v1/webhooks")
+
+	routes.Use(middlewares.JwtAuthMiddleware(db))
+	routes.Use(middlewares.RequireScopes("webhooks:read", "webhooks:write"))
+	routes.GET("/", h.GetWebhooks)
+	routes.POST("/", h.AddWebhook)
+	routes.GET("/:id", h.GetWebhook)
+	routes.PATCH("/:id", h.UpdateWebhook)
+	routes.DELETE("/:id", h.DeleteWebhook)
+	routes.GET("/:id/deliveries", h.GetDeliveries)
+	routes.POST("/:id/deliveries/:deliveryId/redeliver", h.Redeliver)
+}
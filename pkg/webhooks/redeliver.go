@@ -0,0 +1,89 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (h handler) Redeliver(c *gin.Context) {
+	deliveryId := c.Param("deliveryId")
+	webhook := h.findWebhook(c)
+
+	if webhook == nil {
+		return
+	}
+
+	if webhook.Active == nil || !*webhook.Active {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"error": "webhook is disabled, enable it before redelivering",
+		})
+
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(deliveryId)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	deliveriesCollection := h.DB.Collection("webhook_deliveries")
+
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "webhook_id", Value: webhook.Id},
+	}
+
+	var original models.WebhookDelivery
+
+	if err := deliveriesCollection.FindOne(context.TODO(), filter).Decode(&original); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("delivery not found with id '%s'", deliveryId),
+			})
+
+			return
+		}
+
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	status := models.DeliveryPending
+	attempts := 0
+	now := time.Now()
+
+	d := models.WebhookDelivery{
+		WebhookId:     webhook.Id,
+		UserId:        webhook.UserId,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        &status,
+		Attempts:      &attempts,
+		RedeliveryOf:  original.Id,
+		NextAttemptAt: &now,
+		CreatedAt:     &now,
+		UpdatedAt:     &now,
+	}
+
+	req, err := deliveriesCollection.InsertOne(context.TODO(), d)
+
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "delivery queued successfully",
+		"id":      req.InsertedID,
+	})
+}
@@ -0,0 +1,82 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Bryan-an/tasker-backend/pkg/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type updateInput struct {
+	URL    *string   `json:"url" binding:"omitempty,url"`
+	Events *[]string `json:"events" binding:"omitempty,min=1,dive,oneof=task.created task.updated task.completed task.deleted task.restored task.archived task.unarchived task.purged settings.updated"`
+	Active *bool     `json:"active"`
+}
+
+func (h handler) UpdateWebhook(c *gin.Context) {
+	var input updateInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+
+		if errors.As(err, &ve) {
+			out := utils.FillErrors(ve)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+		} else {
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+
+		return
+	}
+
+	webhook := h.findWebhook(c)
+
+	if webhook == nil {
+		return
+	}
+
+	data := bson.D{{Key: "updated_at", Value: time.Now()}}
+	unset := bson.D{}
+
+	if input.URL != nil {
+		if out := validateURL(*input.URL); out != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": out})
+			return
+		}
+
+		data = append(data, bson.E{Key: "url", Value: input.URL})
+	}
+
+	if input.Events != nil {
+		data = append(data, bson.E{Key: "events", Value: input.Events})
+	}
+
+	if input.Active != nil {
+		data = append(data, bson.E{Key: "active", Value: input.Active})
+
+		if *input.Active {
+			data = append(data, bson.E{Key: "consecutive_failures", Value: 0})
+			unset = append(unset, bson.E{Key: "disabled_at", Value: ""})
+		}
+	}
+
+	update := bson.D{{Key: "$set", Value: data}}
+
+	if len(unset) > 0 {
+		update = append(update, bson.E{Key: "$unset", Value: unset})
+	}
+
+	if _, err := h.DB.Collection("webhooks").UpdateByID(context.TODO(), webhook.Id, update); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "webhook updated successfully",
+	})
+}